@@ -0,0 +1,379 @@
+package broadcast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrViewsUnsupported is returned by CollectViews when the Sender cannot
+// report view counts. The Telegram Bot API does not expose channel post
+// views, so TelegramSender does not implement ViewCounter.
+var ErrViewsUnsupported = errors.New("sender does not report view counts")
+
+// ViewCounter is implemented by senders that can report how often the posted
+// messages were viewed.
+type ViewCounter interface {
+	Views(ctx context.Context, channel string, messageIDs []int) (int, error)
+}
+
+// Counters holds the engagement collected for one broadcast.
+type Counters struct {
+	Clicks int
+	Views  int
+}
+
+// AnalyticsRepo stores click and view counts per broadcast record ID.
+type AnalyticsRepo interface {
+	AddClick(ctx context.Context, recordID string) error
+	SetViews(ctx context.Context, recordID string, views int) error
+	Counters(ctx context.Context) (map[string]Counters, error)
+}
+
+// AnalyticsObserver receives engagement updates, e.g. to export them as
+// Prometheus metrics. Per-vacancy updates only cover published broadcasts;
+// ForgetVacancy is called once one is retracted or expires, so the observer
+// can drop its series.
+type AnalyticsObserver interface {
+	ObserveClick(channel, company string)
+	// ObserveViews reports the total views of the broadcasts a CollectViews
+	// run refreshed for a channel and company.
+	ObserveViews(channel, company string, views int)
+	ObserveVacancyClick(recordID string)
+	ObserveVacancyViews(recordID string, views int)
+	ForgetVacancy(recordID string)
+}
+
+// VacancyStats is the engagement of a single broadcast.
+type VacancyStats struct {
+	RecordID string
+	Title    string
+	Company  string
+	Channel  string
+	Status   RecordStatus
+	Clicks   int
+	Views    int
+}
+
+// CompanyStats aggregates the engagement of a company's broadcasts.
+type CompanyStats struct {
+	Company    string
+	Broadcasts int
+	Clicks     int
+	Views      int
+}
+
+// WithAnalytics records apply-link clicks and view counts in store and
+// reports them to observer, which may be nil.
+func (s *Service) WithAnalytics(store AnalyticsRepo, observer AnalyticsObserver) *Service {
+	s.analytics = store
+	s.observer = observer
+	return s
+}
+
+// WithApplyTracking makes cards link to baseURL + "/apply/<record ID>"
+// instead of the posting's own apply target. Serve ApplyHandler under that
+// path to count the clicks and redirect.
+func (s *Service) WithApplyTracking(baseURL string) *Service {
+	s.trackingURL = strings.TrimRight(baseURL, "/")
+	return s
+}
+
+// ApplyTarget returns where candidates apply for a posting: its ApplyURL,
+// or a link derived from a URL, @username or e-mail contact.
+func ApplyTarget(posting JobPosting) string {
+	for _, candidate := range []string{posting.ApplyURL, posting.Contact} {
+		candidate = strings.TrimSpace(candidate)
+		switch {
+		case strings.HasPrefix(candidate, "https://"), strings.HasPrefix(candidate, "http://"):
+			return candidate
+		case strings.HasPrefix(candidate, "@") && len(candidate) > 1 && !strings.ContainsAny(candidate, " /"):
+			return "https://t.me/" + candidate[1:]
+		case strings.Count(candidate, "@") == 1 && !strings.ContainsAny(candidate, " /"):
+			return "mailto:" + candidate
+		}
+	}
+	return ""
+}
+
+// renderCard formats a record's card for its channel. With apply tracking
+// enabled, the posting's apply link is replaced by the tracked redirect.
+func (s *Service) renderCard(record BroadcastRecord) string {
+	job := record.Job
+	if s.trackingURL != "" && ApplyTarget(job) != "" {
+		job.ApplyURL = s.trackingURL + "/apply/" + record.ID
+	}
+	return s.formatterFor(record.Channel)(job, record.Summary)
+}
+
+// ApplyHandler serves tracked apply links of the form /apply/<record ID>: it
+// counts the click and redirects to the posting's apply target.
+func (s *Service) ApplyHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+
+		id := path.Base(r.URL.Path)
+		record, err := s.repo.Get(r.Context(), id)
+		if errors.Is(err, ErrNotFound) {
+			http.NotFound(w, r)
+			return
+		}
+		if err != nil {
+			http.Error(w, "lookup failed", http.StatusInternalServerError)
+			return
+		}
+		target := ApplyTarget(record.Job)
+		if target == "" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if s.analytics != nil {
+			if err := s.analytics.AddClick(r.Context(), record.ID); err != nil {
+				log.Printf("broadcast analytics: count click for %s: %v", record.ID, err)
+			}
+		}
+		if s.observer != nil {
+			s.observer.ObserveClick(record.Channel, record.Job.Company)
+			if record.Status == StatusSent || record.Status == StatusEdited {
+				s.observer.ObserveVacancyClick(record.ID)
+			}
+		}
+		http.Redirect(w, r, target, http.StatusFound)
+	})
+}
+
+// CollectViews refreshes the view counts of the broadcasts published since
+// the given time and returns how many were updated. It returns
+// ErrViewsUnsupported when the Sender is not a ViewCounter.
+func (s *Service) CollectViews(ctx context.Context, since time.Time) (int, error) {
+	counter, ok := s.sender.(ViewCounter)
+	if !ok {
+		return 0, ErrViewsUnsupported
+	}
+	if s.analytics == nil {
+		return 0, errors.New("analytics store is not configured")
+	}
+
+	records, err := s.repo.ListSince(ctx, since)
+	if err != nil {
+		return 0, fmt.Errorf("list broadcasts: %w", err)
+	}
+
+	type group struct{ channel, company string }
+	var (
+		updated int
+		errs    []error
+		totals  = make(map[group]int)
+	)
+	for _, record := range records {
+		if (record.Status != StatusSent && record.Status != StatusEdited) || len(record.MessageIDs) == 0 {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return updated, err
+		}
+
+		views, err := counter.Views(ctx, record.Channel, record.MessageIDs)
+		if err == nil {
+			err = s.analytics.SetViews(ctx, record.ID, views)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("broadcast %s: %w", record.ID, err))
+			continue
+		}
+		totals[group{record.Channel, record.Job.Company}] += views
+		if s.observer != nil {
+			s.observer.ObserveVacancyViews(record.ID, views)
+		}
+		updated++
+	}
+	if s.observer != nil {
+		for g, views := range totals {
+			s.observer.ObserveViews(g.channel, g.company, views)
+		}
+	}
+	return updated, errors.Join(errs...)
+}
+
+// forgetVacancy drops the per-vacancy metrics of a retracted or expired
+// broadcast.
+func (s *Service) forgetVacancy(record BroadcastRecord) {
+	if s.observer != nil {
+		s.observer.ForgetVacancy(record.ID)
+	}
+}
+
+// VacancyStats returns the engagement of every published broadcast, most
+// clicked first.
+func (s *Service) VacancyStats(ctx context.Context) ([]VacancyStats, error) {
+	if s.analytics == nil {
+		return nil, errors.New("analytics store is not configured")
+	}
+	records, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list broadcasts: %w", err)
+	}
+	counters, err := s.analytics.Counters(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load analytics: %w", err)
+	}
+
+	latest := make(map[string]BroadcastRecord, len(records))
+	for _, record := range records {
+		latest[record.ID] = record
+	}
+	stats := make([]VacancyStats, 0, len(latest))
+	for id, record := range latest {
+		if len(record.MessageIDs) == 0 && record.LastSentAt == nil {
+			continue
+		}
+		c := counters[id]
+		stats = append(stats, VacancyStats{
+			RecordID: id,
+			Title:    record.Job.Title,
+			Company:  record.Job.Company,
+			Channel:  record.Channel,
+			Status:   record.Status,
+			Clicks:   c.Clicks,
+			Views:    c.Views,
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Clicks != stats[j].Clicks {
+			return stats[i].Clicks > stats[j].Clicks
+		}
+		return stats[i].RecordID < stats[j].RecordID
+	})
+	return stats, nil
+}
+
+// CompanyStats sums VacancyStats per company, most clicked first.
+func (s *Service) CompanyStats(ctx context.Context) ([]CompanyStats, error) {
+	vacancies, err := s.VacancyStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byCompany := make(map[string]*CompanyStats)
+	var companies []*CompanyStats
+	for _, v := range vacancies {
+		c, ok := byCompany[v.Company]
+		if !ok {
+			c = &CompanyStats{Company: v.Company}
+			byCompany[v.Company] = c
+			companies = append(companies, c)
+		}
+		c.Broadcasts++
+		c.Clicks += v.Clicks
+		c.Views += v.Views
+	}
+
+	stats := make([]CompanyStats, 0, len(companies))
+	for _, c := range companies {
+		stats = append(stats, *c)
+	}
+	sort.SliceStable(stats, func(i, j int) bool {
+		return stats[i].Clicks > stats[j].Clicks
+	})
+	return stats, nil
+}
+
+// ViewCollector periodically refreshes view counts through the Service.
+type ViewCollector struct {
+	service  *Service
+	interval time.Duration
+	lookback time.Duration
+	logger   *log.Logger
+}
+
+// NewViewCollector constructs a collector that refreshes the broadcasts of
+// the last lookback period every interval.
+func NewViewCollector(service *Service, interval, lookback time.Duration, logger *log.Logger) *ViewCollector {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	if lookback <= 0 {
+		lookback = 30 * 24 * time.Hour
+	}
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &ViewCollector{service: service, interval: interval, lookback: lookback, logger: logger}
+}
+
+// Run collects view counts until ctx is cancelled. It returns nil straight
+// away when the sender cannot report views.
+func (c *ViewCollector) Run(ctx context.Context) error {
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		_, err := c.service.CollectViews(ctx, c.service.clock().Add(-c.lookback))
+		if errors.Is(err, ErrViewsUnsupported) {
+			c.logger.Printf("broadcast views: %v; view collection disabled", err)
+			return nil
+		}
+		if err != nil && ctx.Err() == nil {
+			c.logger.Printf("broadcast views: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// MemoryAnalytics keeps counters in memory for tests and local runs.
+type MemoryAnalytics struct {
+	mu       sync.Mutex
+	counters map[string]Counters
+}
+
+// NewMemoryAnalytics constructs an empty in-memory analytics store.
+func NewMemoryAnalytics() *MemoryAnalytics {
+	return &MemoryAnalytics{counters: make(map[string]Counters)}
+}
+
+// AddClick implements AnalyticsRepo.
+func (m *MemoryAnalytics) AddClick(_ context.Context, recordID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c := m.counters[recordID]
+	c.Clicks++
+	m.counters[recordID] = c
+	return nil
+}
+
+// SetViews implements AnalyticsRepo.
+func (m *MemoryAnalytics) SetViews(_ context.Context, recordID string, views int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	c := m.counters[recordID]
+	c.Views = views
+	m.counters[recordID] = c
+	return nil
+}
+
+// Counters implements AnalyticsRepo.
+func (m *MemoryAnalytics) Counters(_ context.Context) (map[string]Counters, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make(map[string]Counters, len(m.counters))
+	for id, c := range m.counters {
+		out[id] = c
+	}
+	return out, nil
+}
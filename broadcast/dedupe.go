@@ -0,0 +1,174 @@
+package broadcast
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrDuplicate is wrapped by DuplicateError when a posting repeats a recent broadcast.
+var ErrDuplicate = errors.New("duplicate vacancy")
+
+// DuplicateError reports the earlier broadcast a posting duplicates.
+type DuplicateError struct {
+	OriginalID string
+	Similarity float64
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("%v of broadcast %s (similarity %.2f)", ErrDuplicate, e.OriginalID, e.Similarity)
+}
+
+func (e *DuplicateError) Unwrap() error {
+	return ErrDuplicate
+}
+
+// DuplicateAction selects what happens to a posting detected as a duplicate.
+type DuplicateAction int
+
+const (
+	// DuplicateReject returns a DuplicateError without saving a record.
+	DuplicateReject DuplicateAction = iota
+	// DuplicateLink saves the posting as StatusDuplicate pointing at the
+	// original broadcast instead of sending it.
+	DuplicateLink
+)
+
+// DuplicatePolicy configures duplicate vacancy detection.
+type DuplicatePolicy struct {
+	// Disabled turns detection off entirely.
+	Disabled bool
+	// Threshold is the similarity (0-1) at or above which postings match.
+	Threshold float64
+	// Lookback limits the comparison to broadcasts created within this window.
+	Lookback time.Duration
+	Action   DuplicateAction
+}
+
+// DefaultDuplicatePolicy rejects postings that closely match a broadcast from
+// the last two weeks.
+func DefaultDuplicatePolicy() DuplicatePolicy {
+	return DuplicatePolicy{
+		Threshold: 0.85,
+		Lookback:  14 * 24 * time.Hour,
+		Action:    DuplicateReject,
+	}
+}
+
+// Fingerprint returns a stable hash of the normalized title, company,
+// location, and description, so trivially reformatted reposts share it.
+func Fingerprint(posting JobPosting) string {
+	sum := sha256.Sum256([]byte(strings.Join([]string{
+		normalizeText(posting.Title),
+		normalizeText(posting.Company),
+		normalizeText(posting.Location),
+		normalizeText(posting.Description),
+	}, "|")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Similarity scores how alike two postings are from 0 (unrelated) to 1
+// (identical after normalization). Title and company carry the most weight;
+// descriptions are compared by word overlap so small rewordings still match.
+// Fields empty in both postings are left out and the remaining weights
+// rescaled, so missing data does not count as a match.
+func Similarity(a, b JobPosting) float64 {
+	if Fingerprint(a) == Fingerprint(b) {
+		return 1
+	}
+	fields := []struct {
+		weight float64
+		a, b   string
+	}{
+		{0.35, a.Title, b.Title},
+		{0.25, a.Company, b.Company},
+		{0.1, a.Location, b.Location},
+		{0.3, a.Description, b.Description},
+	}
+	var score, total float64
+	for _, field := range fields {
+		if len(words(field.a)) == 0 && len(words(field.b)) == 0 {
+			continue
+		}
+		score += field.weight * wordOverlap(field.a, field.b)
+		total += field.weight
+	}
+	if total == 0 {
+		return 0
+	}
+	return score / total
+}
+
+// findDuplicate returns the most similar recent broadcast on channel that
+// meets the policy threshold, or nil when the posting is new there.
+func (s *Service) findDuplicate(ctx context.Context, posting JobPosting, channel string) (*DuplicateError, error) {
+	policy := s.duplicates
+	if policy.Disabled {
+		return nil, nil
+	}
+
+	recent, err := s.repo.ListSince(ctx, s.clock().Add(-policy.Lookback))
+	if err != nil {
+		return nil, fmt.Errorf("list recent broadcasts: %w", err)
+	}
+
+	var best *DuplicateError
+	for _, record := range recent {
+		if record.Channel != channel {
+			continue
+		}
+		switch record.Status {
+		case StatusFailed, StatusDryRun, StatusRetracted, StatusDuplicate, StatusRejected, StatusExpired:
+			continue
+		}
+		score := Similarity(posting, record.Job)
+		if score >= policy.Threshold && (best == nil || score > best.Similarity) {
+			best = &DuplicateError{OriginalID: record.ID, Similarity: score}
+		}
+	}
+	return best, nil
+}
+
+func normalizeText(text string) string {
+	return strings.Join(words(text), " ")
+}
+
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// wordOverlap is the Jaccard index of the word sets of a and b, or 0 when
+// both are empty.
+func wordOverlap(a, b string) float64 {
+	wa, wb := words(a), words(b)
+	if len(wa) == 0 && len(wb) == 0 {
+		return 0
+	}
+
+	set := make(map[string]bool, len(wa))
+	for _, w := range wa {
+		set[w] = true
+	}
+	union := len(set)
+	shared := 0
+	seen := make(map[string]bool, len(wb))
+	for _, w := range wb {
+		if seen[w] {
+			continue
+		}
+		seen[w] = true
+		if set[w] {
+			shared++
+		} else {
+			union++
+		}
+	}
+	return float64(shared) / float64(union)
+}
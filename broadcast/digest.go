@@ -0,0 +1,419 @@
+package broadcast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// ErrEmptyDigest is returned by PostDigest when no vacancy was published in
+// the digest period; nothing is sent in that case.
+var ErrEmptyDigest = errors.New("no vacancies published in the digest period")
+
+// Seniority levels used to group digest entries, in the order they appear.
+const (
+	SeniorityIntern = "intern"
+	SeniorityJunior = "junior"
+	SeniorityMiddle = "middle"
+	SenioritySenior = "senior"
+	SeniorityLead   = "lead"
+	SeniorityOther  = "other"
+)
+
+var seniorityOrder = []string{SeniorityIntern, SeniorityJunior, SeniorityMiddle, SenioritySenior, SeniorityLead, SeniorityOther}
+
+// seniorityPatterns are checked in order against the title and then the
+// experience requirement, so "Senior Go Team Lead" is a lead position.
+var seniorityPatterns = []struct {
+	level   string
+	pattern *regexp.Regexp
+}{
+	{SeniorityLead, regexp.MustCompile(`(?i)\b(?:lead|teamlead|principal|head of|architect)\b`)},
+	{SenioritySenior, regexp.MustCompile(`(?i)\b(?:senior|sr)\b|старший`)},
+	{SeniorityMiddle, regexp.MustCompile(`(?i)\b(?:middle|mid)\b`)},
+	{SeniorityJunior, regexp.MustCompile(`(?i)\b(?:junior|jr)\b|младший`)},
+	{SeniorityIntern, regexp.MustCompile(`(?i)\b(?:intern|internship|trainee)\b|стаж[её]р|amaliyotchi`)},
+}
+
+var yearsPattern = regexp.MustCompile(`\d+`)
+
+// SeniorityOf classifies a posting by keywords in its title or experience
+// requirement, falling back to the number of years asked for.
+func SeniorityOf(posting JobPosting) string {
+	for _, text := range []string{posting.Title, posting.Experience} {
+		for _, level := range seniorityPatterns {
+			if level.pattern.MatchString(text) {
+				return level.level
+			}
+		}
+	}
+
+	years, err := strconv.Atoi(yearsPattern.FindString(posting.Experience))
+	switch {
+	case err != nil:
+		return SeniorityOther
+	case years <= 1:
+		return SeniorityJunior
+	case years <= 3:
+		return SeniorityMiddle
+	default:
+		return SenioritySenior
+	}
+}
+
+// PostLink returns the public link to a channel post, or "" when the channel
+// is neither an @username nor a -100 prefixed supergroup or channel ID.
+func PostLink(channel string, messageID int) string {
+	channel = strings.TrimSpace(channel)
+	switch {
+	case messageID <= 0:
+		return ""
+	case strings.HasPrefix(channel, "@") && len(channel) > 1:
+		return fmt.Sprintf("https://t.me/%s/%d", channel[1:], messageID)
+	case strings.HasPrefix(channel, "-100") && len(channel) > 4:
+		return fmt.Sprintf("https://t.me/c/%s/%d", channel[4:], messageID)
+	default:
+		return ""
+	}
+}
+
+// DigestData is the value digest templates are executed with. Like CardData,
+// text fields are already escaped for MarkdownV2 and Link is escaped as a
+// link target.
+type DigestData struct {
+	Since    string
+	Until    string
+	Count    int
+	Sections []DigestSection
+}
+
+// DigestSection groups the vacancies of one seniority level by location.
+type DigestSection struct {
+	// Seniority is one of the Seniority constants; templates translate it.
+	Seniority string
+	Locations []DigestLocation
+}
+
+// DigestLocation lists the vacancies of a section in one location. Location
+// is empty for postings that do not name one.
+type DigestLocation struct {
+	Location  string
+	Vacancies []DigestVacancy
+}
+
+// DigestVacancy is a single digest entry linking to the original post.
+type DigestVacancy struct {
+	Title   string
+	Company string
+	Salary  string
+	Link    string
+}
+
+// DigestTemplate renders weekly digest posts from a text/template.
+type DigestTemplate struct {
+	name string
+	tmpl *template.Template
+}
+
+// ParseDigestTemplate parses and validates a digest template against a sample
+// and an empty digest, as ParseCardTemplate does for cards.
+func ParseDigestTemplate(name, text string) (*DigestTemplate, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse digest template %s: %w", name, err)
+	}
+	digest := &DigestTemplate{name: name, tmpl: tmpl}
+
+	sample := DigestData{
+		Since: EscapeMarkdownV2("2024-05-06"),
+		Until: EscapeMarkdownV2("2024-05-13"),
+		Count: 2,
+		Sections: []DigestSection{
+			{Seniority: SenioritySenior, Locations: []DigestLocation{{
+				Location:  EscapeMarkdownV2("Tashkent"),
+				Vacancies: []DigestVacancy{{Title: EscapeMarkdownV2("Senior Go Developer (Payments)"), Company: EscapeMarkdownV2("Example Inc."), Salary: EscapeMarkdownV2("$3,000-$4,500"), Link: "https://t.me/golangjobsuz/42"}},
+			}}},
+			{Seniority: SeniorityOther, Locations: []DigestLocation{{
+				Vacancies: []DigestVacancy{{Title: EscapeMarkdownV2("Go Developer")}},
+			}}},
+		},
+	}
+	for i, data := range []DigestData{sample, {}} {
+		out, err := digest.Render(data)
+		if err != nil {
+			return nil, fmt.Errorf("validate digest template %s: %w", name, err)
+		}
+		if i == 0 && out == "" {
+			return nil, fmt.Errorf("validate digest template %s: renders an empty digest", name)
+		}
+		if err := checkMarkdownV2(out); err != nil {
+			return nil, fmt.Errorf("validate digest template %s: %w", name, err)
+		}
+	}
+	return digest, nil
+}
+
+// LoadDigestTemplate reads and validates a digest template file.
+func LoadDigestTemplate(path string) (*DigestTemplate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read digest template: %w", err)
+	}
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return ParseDigestTemplate(name, string(data))
+}
+
+// BuiltinDigestTemplate returns the built-in digest template for one of
+// CardLanguages.
+func BuiltinDigestTemplate(lang string) (*DigestTemplate, error) {
+	data, err := builtinTemplates.ReadFile("templates/digest_" + lang + ".tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("no built-in digest template for language %q", lang)
+	}
+	return ParseDigestTemplate("digest_"+lang, string(data))
+}
+
+// Name returns the template name.
+func (d *DigestTemplate) Name() string {
+	return d.name
+}
+
+// Render executes the template for a digest.
+func (d *DigestTemplate) Render(data DigestData) (string, error) {
+	var b strings.Builder
+	if err := d.tmpl.Execute(&b, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// DigestOptions controls how a digest is compiled and posted.
+type DigestOptions struct {
+	// Period is how far back the digest reaches; zero means seven days.
+	Period time.Duration
+	// Template renders the post; nil uses the built-in English template.
+	Template *DigestTemplate
+	// Channel receives the digest; empty uses the Service's channel.
+	Channel string
+	// DryRun renders the digest without sending it.
+	DryRun bool
+}
+
+// BuildDigest compiles the vacancies published on channel in [since, until)
+// from the broadcast log, grouped by seniority and location. Only records
+// that are still sent or edited are included, so retracted, expired and
+// failed broadcasts are skipped. An empty channel covers every channel and
+// lists a vacancy fanned out to several channels once.
+func (s *Service) BuildDigest(ctx context.Context, channel string, since, until time.Time) (DigestData, error) {
+	records, err := s.repo.ListSentBetween(ctx, since, until)
+	if err != nil {
+		return DigestData{}, fmt.Errorf("list broadcasts: %w", err)
+	}
+
+	latest := make(map[string]BroadcastRecord, len(records))
+	for _, record := range records {
+		latest[record.ID] = record
+	}
+	var published []BroadcastRecord
+	for _, record := range latest {
+		if record.Status != StatusSent && record.Status != StatusEdited {
+			continue
+		}
+		if channel != "" && record.Channel != channel {
+			continue
+		}
+		if !sentBetween(record, since, until) {
+			continue
+		}
+		published = append(published, record)
+	}
+	sort.Slice(published, func(i, j int) bool {
+		return published[i].LastSentAt.Before(*published[j].LastSentAt)
+	})
+	if channel == "" {
+		published = firstPerFingerprint(published)
+	}
+
+	grouped := make(map[string]map[string][]DigestVacancy)
+	for _, record := range published {
+		level := SeniorityOf(record.Job)
+		location := strings.TrimSpace(record.Job.Location)
+		if grouped[level] == nil {
+			grouped[level] = make(map[string][]DigestVacancy)
+		}
+		var link string
+		if len(record.MessageIDs) > 0 {
+			link = linkURLReplacer.Replace(PostLink(record.Channel, record.MessageIDs[0]))
+		}
+		grouped[level][location] = append(grouped[level][location], DigestVacancy{
+			Title:   EscapeMarkdownV2(record.Job.Title),
+			Company: EscapeMarkdownV2(record.Job.Company),
+			Salary:  EscapeMarkdownV2(record.Job.Salary),
+			Link:    link,
+		})
+	}
+
+	data := DigestData{
+		Since: EscapeMarkdownV2(since.Format("2006-01-02")),
+		Until: EscapeMarkdownV2(until.Format("2006-01-02")),
+		Count: len(published),
+	}
+	for _, level := range seniorityOrder {
+		byLocation, ok := grouped[level]
+		if !ok {
+			continue
+		}
+		locations := make([]string, 0, len(byLocation))
+		for location := range byLocation {
+			locations = append(locations, location)
+		}
+		// Postings without a location are listed last.
+		sort.Slice(locations, func(i, j int) bool {
+			if (locations[i] == "") != (locations[j] == "") {
+				return locations[j] == ""
+			}
+			return strings.ToLower(locations[i]) < strings.ToLower(locations[j])
+		})
+
+		section := DigestSection{Seniority: level}
+		for _, location := range locations {
+			section.Locations = append(section.Locations, DigestLocation{
+				Location:  EscapeMarkdownV2(location),
+				Vacancies: byLocation[location],
+			})
+		}
+		data.Sections = append(data.Sections, section)
+	}
+	return data, nil
+}
+
+// PostDigest compiles the digest for the period ending now and posts it. It
+// returns the rendered text, and ErrEmptyDigest without sending when there is
+// nothing to list.
+func (s *Service) PostDigest(ctx context.Context, opts DigestOptions) (string, error) {
+	if opts.Period <= 0 {
+		opts.Period = 7 * 24 * time.Hour
+	}
+	if opts.Template == nil {
+		opts.Template = defaultDigest
+	}
+	if opts.Channel == "" {
+		opts.Channel = s.channel
+	}
+
+	until := s.clock()
+	data, err := s.BuildDigest(ctx, opts.Channel, until.Add(-opts.Period), until)
+	if err != nil {
+		return "", err
+	}
+	if data.Count == 0 {
+		return "", ErrEmptyDigest
+	}
+	text, err := opts.Template.Render(data)
+	if err != nil {
+		return "", fmt.Errorf("render digest: %w", err)
+	}
+	if opts.DryRun {
+		return text, nil
+	}
+	if _, err := s.send(ctx, opts.Channel, text); err != nil {
+		return text, fmt.Errorf("send digest: %w", err)
+	}
+	return text, nil
+}
+
+// firstPerFingerprint keeps the earliest of the records sharing a
+// fingerprint, in order.
+func firstPerFingerprint(records []BroadcastRecord) []BroadcastRecord {
+	seen := make(map[string]bool, len(records))
+	var unique []BroadcastRecord
+	for _, record := range records {
+		key := record.Fingerprint
+		if key == "" {
+			key = Fingerprint(record.Job)
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, record)
+	}
+	return unique
+}
+
+// defaultDigest is the English built-in template used by PostDigest.
+var defaultDigest = func() *DigestTemplate {
+	digest, err := BuiltinDigestTemplate("en")
+	if err != nil {
+		panic(err)
+	}
+	return digest
+}()
+
+// DigestSchedule is a weekly point in time, e.g. Mondays at 09:00 in
+// Asia/Tashkent.
+type DigestSchedule struct {
+	Weekday time.Weekday
+	Hour    int
+	// Location defaults to time.Local.
+	Location *time.Location
+}
+
+// Next returns the first scheduled time strictly after t.
+func (d DigestSchedule) Next(t time.Time) time.Time {
+	loc := d.Location
+	if loc == nil {
+		loc = time.Local
+	}
+	local := t.In(loc)
+	days := (int(d.Weekday) - int(local.Weekday()) + 7) % 7
+	next := time.Date(local.Year(), local.Month(), local.Day()+days, d.Hour, 0, 0, 0, loc)
+	if !next.After(t) {
+		next = next.AddDate(0, 0, 7)
+	}
+	return next
+}
+
+// DigestScheduler posts a digest at every scheduled time. A digest missed
+// while the process was down is not posted late.
+type DigestScheduler struct {
+	service  *Service
+	schedule DigestSchedule
+	opts     DigestOptions
+	logger   *log.Logger
+}
+
+// NewDigestScheduler constructs a scheduler posting with the given options.
+func NewDigestScheduler(service *Service, schedule DigestSchedule, opts DigestOptions, logger *log.Logger) *DigestScheduler {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &DigestScheduler{service: service, schedule: schedule, opts: opts, logger: logger}
+}
+
+// Run posts digests until ctx is cancelled.
+func (d *DigestScheduler) Run(ctx context.Context) error {
+	for {
+		now := d.service.clock()
+		if err := d.service.sleep(ctx, d.schedule.Next(now).Sub(now)); err != nil {
+			return err
+		}
+
+		_, err := d.service.PostDigest(ctx, d.opts)
+		switch {
+		case errors.Is(err, ErrEmptyDigest):
+			d.logger.Printf("broadcast digest: %v; skipped", err)
+		case err != nil && ctx.Err() == nil:
+			d.logger.Printf("broadcast digest: %v", err)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package broadcast
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Dispatcher periodically delivers scheduled broadcasts from the Service's
+// Repo. Run a single dispatcher per repository to avoid double delivery.
+type Dispatcher struct {
+	service  *Service
+	interval time.Duration
+	logger   *log.Logger
+}
+
+// NewDispatcher constructs a Dispatcher polling at the given interval.
+func NewDispatcher(service *Service, interval time.Duration, logger *log.Logger) *Dispatcher {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &Dispatcher{service: service, interval: interval, logger: logger}
+}
+
+// Run dispatches due broadcasts until ctx is cancelled. It checks the Repo
+// immediately on start so records scheduled before a restart are not delayed
+// by a full interval.
+func (d *Dispatcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		processed, err := d.service.DispatchDue(ctx)
+		if err != nil && ctx.Err() == nil {
+			d.logger.Printf("broadcast dispatch: %v", err)
+		}
+		if len(processed) > 0 {
+			d.logger.Printf("broadcast dispatch: processed %d scheduled records", len(processed))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
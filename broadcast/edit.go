@@ -0,0 +1,153 @@
+package broadcast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNotPublished is returned when editing a broadcast that was never posted.
+	ErrNotPublished = errors.New("broadcast has not been published")
+	// ErrRetracted is returned when changing a broadcast that was already retracted.
+	ErrRetracted = errors.New("broadcast was retracted")
+	// ErrNotEditable is returned when the Sender cannot edit or delete posts.
+	ErrNotEditable = errors.New("sender cannot edit published messages")
+)
+
+// closedBanner is prepended to cards of vacancies that are no longer open.
+const closedBanner = "🚫 *CLOSED* 🚫\n\n"
+
+// FormatClosedCard renders the card with a banner marking the vacancy closed.
+func FormatClosedCard(posting JobPosting, summary string) string {
+	return closedBanner + FormatCard(posting, summary)
+}
+
+// EditBroadcast replaces the posting behind a broadcast. Published posts are
+// re-rendered with the channel's card formatter and edited in place through
+// the stored message IDs; scheduled broadcasts and those pending review are
+// simply updated before they go out.
+func (s *Service) EditBroadcast(ctx context.Context, id string, posting JobPosting) (BroadcastRecord, error) {
+	record, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return BroadcastRecord{}, fmt.Errorf("load broadcast %s: %w", id, err)
+	}
+	if record.Status == StatusRetracted || record.Status == StatusExpired {
+		return record, ErrRetracted
+	}
+
+	summary, err := summarizeWith(ctx, s.summarizer, posting)
+	if err != nil {
+		return record, fmt.Errorf("summarize: %w", err)
+	}
+
+	status := record.Status
+	messageIDs := record.MessageIDs
+	switch record.Status {
+	case StatusScheduled, StatusPendingReview:
+	case StatusSent, StatusEdited:
+		editor, err := s.editor(record)
+		if err != nil {
+			return record, err
+		}
+		updated := record
+		updated.Job, updated.Summary = posting, summary.Text
+		messageIDs, err = editor.EditMessages(ctx, record.Channel, record.MessageIDs, s.renderCard(updated))
+		if err != nil {
+			return record, fmt.Errorf("edit broadcast %s: %w", id, err)
+		}
+		status = StatusEdited
+	default:
+		return record, ErrNotPublished
+	}
+
+	record = s.recordChange(record, status)
+	record.Job = posting
+	record.Fingerprint = Fingerprint(posting)
+	record.Summary = summary.Text
+	record.SummaryBy = summary.Summarizer
+	record.SummaryModel = summary.Model
+	record.MessageIDs = messageIDs
+	if err := s.repo.Save(ctx, record); err != nil {
+		return record, fmt.Errorf("save edited record: %w", err)
+	}
+	return record, nil
+}
+
+// RetractBroadcast withdraws a broadcast. With markClosed the channel post is
+// kept and edited to show a CLOSED banner; otherwise it is deleted. Scheduled
+// broadcasts are cancelled before they are sent.
+func (s *Service) RetractBroadcast(ctx context.Context, id string, markClosed bool) (BroadcastRecord, error) {
+	record, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return BroadcastRecord{}, fmt.Errorf("load broadcast %s: %w", id, err)
+	}
+	if record.Status == StatusRetracted || record.Status == StatusExpired {
+		return record, ErrRetracted
+	}
+	return s.retract(ctx, record, markClosed, StatusRetracted)
+}
+
+// retract deletes or closes the record's posts and moves it to status.
+func (s *Service) retract(ctx context.Context, record BroadcastRecord, markClosed bool, status RecordStatus) (BroadcastRecord, error) {
+	messageIDs := record.MessageIDs
+	switch record.Status {
+	case StatusScheduled:
+	case StatusSent, StatusEdited:
+		editor, err := s.editor(record)
+		if err != nil {
+			return record, err
+		}
+		if markClosed {
+			closed := record.Job
+			closed.ApplyURL = ""
+			messageIDs, err = editor.EditMessages(ctx, record.Channel, record.MessageIDs, closedBanner+s.formatterFor(record.Channel)(closed, record.Summary))
+		} else {
+			err = editor.DeleteMessages(ctx, record.Channel, record.MessageIDs)
+			messageIDs = nil
+		}
+		if err != nil {
+			return record, fmt.Errorf("retract broadcast %s: %w", record.ID, err)
+		}
+	default:
+		return record, ErrNotPublished
+	}
+
+	record = s.recordChange(record, status)
+	record.MessageIDs = messageIDs
+	if err := s.repo.Save(ctx, record); err != nil {
+		return record, fmt.Errorf("save %s record: %w", status, err)
+	}
+	s.forgetVacancy(record)
+	return record, nil
+}
+
+// recordChange appends the record's current state to its history and moves
+// it to the given status.
+func (s *Service) recordChange(record BroadcastRecord, status RecordStatus) BroadcastRecord {
+	now := s.clock()
+	record.History = append(record.History, RecordChange{
+		Status:     record.Status,
+		Job:        record.Job,
+		Summary:    record.Summary,
+		MessageIDs: record.MessageIDs,
+		ChangedTo:  status,
+		ChangedAt:  now,
+	})
+	record.Status = status
+	record.UpdatedAt = now
+	return record
+}
+
+// editor returns the sender as an EditableSender when the record's posts can
+// be changed through it.
+func (s *Service) editor(record BroadcastRecord) (EditableSender, error) {
+	editor, ok := s.sender.(EditableSender)
+	if !ok {
+		return nil, ErrNotEditable
+	}
+	if len(record.MessageIDs) == 0 {
+		return nil, fmt.Errorf("%w: no message IDs stored for broadcast %s", ErrNotEditable, record.ID)
+	}
+	return editor, nil
+}
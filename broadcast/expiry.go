@@ -0,0 +1,251 @@
+package broadcast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Golangjobsuz/golangjobsuz/contact"
+)
+
+// ExpiryPolicy configures vacancy lifetimes and "still hiring?" reminders.
+type ExpiryPolicy struct {
+	// DefaultLifetime is applied to postings without ExpiresAt. Zero leaves
+	// such postings open indefinitely.
+	DefaultLifetime time.Duration
+	// RemindBefore is how long before expiry the contact is asked whether the
+	// vacancy is still open.
+	RemindBefore time.Duration
+	// AnswerWindow is the minimum time the contact gets to answer before the
+	// broadcast is closed automatically.
+	AnswerWindow time.Duration
+	// MarkClosed keeps expired posts in the channel with a CLOSED banner
+	// instead of deleting them.
+	MarkClosed bool
+	// Reminders delivers plain-text reminders to the posting's contact, or to
+	// the admins when the contact cannot be reached. Without it reminders are
+	// only recorded on the broadcast, which still expires after AnswerWindow.
+	Reminders contact.Notifier
+}
+
+// DefaultExpiryPolicy gives vacancies 30 days, asks two days before expiry,
+// and marks unanswered vacancies closed.
+func DefaultExpiryPolicy() ExpiryPolicy {
+	return ExpiryPolicy{
+		DefaultLifetime: 30 * 24 * time.Hour,
+		RemindBefore:    2 * 24 * time.Hour,
+		AnswerWindow:    2 * 24 * time.Hour,
+		MarkClosed:      true,
+	}
+}
+
+// WithExpiryPolicy enables automatic expiry. Zero RemindBefore or
+// AnswerWindow values keep the defaults.
+func (s *Service) WithExpiryPolicy(policy ExpiryPolicy) *Service {
+	defaults := DefaultExpiryPolicy()
+	if policy.RemindBefore <= 0 {
+		policy.RemindBefore = defaults.RemindBefore
+	}
+	if policy.AnswerWindow <= 0 {
+		policy.AnswerWindow = defaults.AnswerWindow
+	}
+	s.expiry = &policy
+	return s
+}
+
+// ExpiresAt returns when the record's vacancy expires, or the zero time when
+// it does not.
+func (s *Service) ExpiresAt(record BroadcastRecord) time.Time {
+	if !record.Job.ExpiresAt.IsZero() {
+		return record.Job.ExpiresAt
+	}
+	if s.expiry == nil || s.expiry.DefaultLifetime <= 0 {
+		return time.Time{}
+	}
+	published := record.CreatedAt
+	if record.LastSentAt != nil {
+		published = *record.LastSentAt
+	}
+	return published.Add(s.expiry.DefaultLifetime)
+}
+
+// CheckExpiry sends reminders for published vacancies nearing expiry and
+// closes those whose reminder went unanswered. It returns the records it
+// changed; failures are joined into the error and the rest still processed.
+func (s *Service) CheckExpiry(ctx context.Context) ([]BroadcastRecord, error) {
+	if s.expiry == nil {
+		return nil, nil
+	}
+
+	var published []BroadcastRecord
+	for _, status := range []RecordStatus{StatusSent, StatusEdited} {
+		records, err := s.repo.ListByStatus(ctx, status)
+		if err != nil {
+			return nil, fmt.Errorf("list %s records: %w", status, err)
+		}
+		published = append(published, records...)
+	}
+
+	now := s.clock()
+	var (
+		changed []BroadcastRecord
+		errs    []error
+	)
+	for _, record := range published {
+		if err := ctx.Err(); err != nil {
+			return changed, err
+		}
+		expiresAt := s.ExpiresAt(record)
+		if expiresAt.IsZero() {
+			continue
+		}
+
+		var err error
+		switch {
+		case record.ReminderSentAt == nil && !now.Before(expiresAt.Add(-s.expiry.RemindBefore)):
+			record, err = s.remind(ctx, record, expiresAt)
+		case record.ReminderSentAt != nil && !now.Before(expiresAt) && !now.Before(record.ReminderSentAt.Add(s.expiry.AnswerWindow)):
+			record, err = s.expire(ctx, record)
+		default:
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("broadcast %s: %w", record.ID, err))
+			continue
+		}
+		changed = append(changed, record)
+	}
+	return changed, errors.Join(errs...)
+}
+
+// ExtendBroadcast records that a vacancy is still open: it moves the expiry
+// to until (or a full DefaultLifetime from now when until is zero) and clears
+// the pending reminder.
+func (s *Service) ExtendBroadcast(ctx context.Context, id string, until time.Time) (BroadcastRecord, error) {
+	record, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return BroadcastRecord{}, fmt.Errorf("load broadcast %s: %w", id, err)
+	}
+	if record.Status != StatusSent && record.Status != StatusEdited {
+		return record, fmt.Errorf("%w: broadcast %s is %s", ErrNotPublished, id, record.Status)
+	}
+
+	now := s.clock()
+	if until.IsZero() {
+		lifetime := DefaultExpiryPolicy().DefaultLifetime
+		if s.expiry != nil && s.expiry.DefaultLifetime > 0 {
+			lifetime = s.expiry.DefaultLifetime
+		}
+		until = now.Add(lifetime)
+	}
+	record.Job.ExpiresAt = until
+	record.ReminderSentAt = nil
+	record.UpdatedAt = now
+	if err := s.repo.Save(ctx, record); err != nil {
+		return record, fmt.Errorf("save extended record: %w", err)
+	}
+	return record, nil
+}
+
+// expire closes or deletes an unanswered vacancy. When the sender cannot
+// change posts the record is still marked expired so it is not retried.
+func (s *Service) expire(ctx context.Context, record BroadcastRecord) (BroadcastRecord, error) {
+	expired, err := s.retract(ctx, record, s.expiry.MarkClosed, StatusExpired)
+	if !errors.Is(err, ErrNotEditable) {
+		return expired, err
+	}
+
+	record = s.recordChange(record, StatusExpired)
+	record.Errors = append(record.Errors, "expired without updating the channel post: "+err.Error())
+	if err := s.repo.Save(ctx, record); err != nil {
+		return record, fmt.Errorf("save expired record: %w", err)
+	}
+	s.forgetVacancy(record)
+	return record, nil
+}
+
+// remind asks the posting's contact whether the vacancy is still open. The
+// answer window starts even when nobody could be reminded, so the vacancy
+// still expires; the delivery failure is kept in the record's errors.
+func (s *Service) remind(ctx context.Context, record BroadcastRecord, expiresAt time.Time) (BroadcastRecord, error) {
+	message := fmt.Sprintf(
+		"Is the vacancy %q at %s still open? It will be closed on %s unless you reply /still_hiring %s. Reply /filled %s once it is filled.",
+		record.Job.Title, record.Job.Company, expiresAt.Format("2006-01-02"), record.ID, record.ID,
+	)
+
+	now := s.clock()
+	if err := s.deliverReminder(ctx, record, message); err != nil {
+		log.Printf("broadcast expiry: reminder for %s not delivered: %v", record.ID, err)
+		record.Errors = append(record.Errors, "expiry reminder not delivered: "+err.Error())
+	}
+	record.ReminderSentAt = &now
+	record.UpdatedAt = now
+	if err := s.repo.Save(ctx, record); err != nil {
+		return record, fmt.Errorf("save reminded record: %w", err)
+	}
+	return record, nil
+}
+
+// deliverReminder sends the reminder to the posting's contact, falling back
+// to the admins when the contact is missing or unreachable, such as an email
+// address or phone number.
+func (s *Service) deliverReminder(ctx context.Context, record BroadcastRecord, message string) error {
+	reminders := s.expiry.Reminders
+	if reminders == nil {
+		return errors.New("no reminder notifier configured")
+	}
+	if record.Job.Contact == "" {
+		return reminders.NotifyAdmin(ctx, message)
+	}
+	err := reminders.NotifySeeker(ctx, record.Job.Contact, message)
+	if err == nil {
+		return nil
+	}
+	adminMessage := fmt.Sprintf("The expiry reminder could not reach %s (%v):\n\n%s", record.Job.Contact, err, message)
+	if adminErr := reminders.NotifyAdmin(ctx, adminMessage); adminErr != nil {
+		return errors.Join(err, adminErr)
+	}
+	return nil
+}
+
+// ExpiryWatcher periodically runs CheckExpiry.
+type ExpiryWatcher struct {
+	service  *Service
+	interval time.Duration
+	logger   *log.Logger
+}
+
+// NewExpiryWatcher constructs a watcher checking at the given interval.
+func NewExpiryWatcher(service *Service, interval time.Duration, logger *log.Logger) *ExpiryWatcher {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &ExpiryWatcher{service: service, interval: interval, logger: logger}
+}
+
+// Run checks for expiring broadcasts until ctx is cancelled.
+func (w *ExpiryWatcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		changed, err := w.service.CheckExpiry(ctx)
+		if err != nil && ctx.Err() == nil {
+			w.logger.Printf("broadcast expiry: %v", err)
+		}
+		if len(changed) > 0 {
+			w.logger.Printf("broadcast expiry: updated %d records", len(changed))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
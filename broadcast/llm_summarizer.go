@@ -0,0 +1,141 @@
+package broadcast
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// Completer generates free-form text from a prompt. The OpenAI and Gemini
+// clients in internal/extraction satisfy it; it returns the text and the model
+// that produced it.
+type Completer interface {
+	Complete(ctx context.Context, system, prompt string) (string, string, error)
+}
+
+// Summary is a generated description together with its provenance.
+type Summary struct {
+	Text       string
+	Summarizer string
+	Model      string
+}
+
+// AttributedSummarizer is implemented by summarizers that report which
+// implementation and model produced the text.
+type AttributedSummarizer interface {
+	SummarizeAttributed(ctx context.Context, posting JobPosting) (Summary, error)
+}
+
+const summarySystemPrompt = "You write short, factual summaries of job vacancies for a Telegram channel of Go developers."
+
+// LLMSummarizer asks an LLM for a vacancy summary and falls back to
+// SimpleSummarizer when the call fails, times out, or returns nothing.
+type LLMSummarizer struct {
+	client    Completer
+	provider  string
+	maxLength int
+	timeout   time.Duration
+	fallback  Summarizer
+	logger    *log.Logger
+}
+
+// NewLLMSummarizer constructs a summarizer for the named provider (e.g.
+// "openai" or "gemini"). Summaries longer than maxLength runes are trimmed.
+func NewLLMSummarizer(client Completer, provider string, maxLength int, timeout time.Duration, logger *log.Logger) *LLMSummarizer {
+	if maxLength <= 0 {
+		maxLength = 280
+	}
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &LLMSummarizer{
+		client:    client,
+		provider:  provider,
+		maxLength: maxLength,
+		timeout:   timeout,
+		fallback:  SimpleSummarizer{},
+		logger:    logger,
+	}
+}
+
+// Summarize implements Summarizer.
+func (l *LLMSummarizer) Summarize(ctx context.Context, posting JobPosting) (string, error) {
+	summary, err := l.SummarizeAttributed(ctx, posting)
+	return summary.Text, err
+}
+
+// SummarizeAttributed implements AttributedSummarizer.
+func (l *LLMSummarizer) SummarizeAttributed(ctx context.Context, posting JobPosting) (Summary, error) {
+	callCtx, cancel := context.WithTimeout(ctx, l.timeout)
+	defer cancel()
+
+	text, model, err := l.client.Complete(callCtx, summarySystemPrompt, summaryPrompt(posting, l.maxLength))
+	text = cleanSummary(text)
+	if err == nil && text != "" {
+		return Summary{Text: truncate(text, l.maxLength), Summarizer: l.provider, Model: model}, nil
+	}
+
+	if err == nil {
+		err = fmt.Errorf("%s returned an empty summary", l.provider)
+	}
+	l.logger.Printf("llm summarizer %s failed, using fallback: %v", l.provider, err)
+
+	fallback, fbErr := summarizeWith(ctx, l.fallback, posting)
+	if fbErr != nil {
+		return Summary{}, fmt.Errorf("fallback summarizer: %w", fbErr)
+	}
+	return fallback, nil
+}
+
+func summaryPrompt(posting JobPosting, maxLength int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Summarize this vacancy in one or two sentences, at most %d characters. ", maxLength)
+	b.WriteString("Mention the role, the main stack or responsibilities, and anything notable such as remote work. ")
+	b.WriteString("Do not repeat the salary or contact details, do not use Markdown, and reply with the summary only.\n\n")
+	fmt.Fprintf(&b, "Title: %s\nCompany: %s\n", posting.Title, posting.Company)
+	if posting.Location != "" {
+		fmt.Fprintf(&b, "Location: %s\n", posting.Location)
+	}
+	if posting.Experience != "" {
+		fmt.Fprintf(&b, "Experience: %s\n", posting.Experience)
+	}
+	fmt.Fprintf(&b, "Description:\n%s\n", posting.Description)
+	return b.String()
+}
+
+// cleanSummary collapses the model output into a single unquoted line.
+func cleanSummary(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	return strings.Trim(text, `"'`)
+}
+
+// truncate shortens text to at most max runes, cutting at a word boundary.
+func truncate(text string, max int) string {
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	cut := string(runes[:max-1])
+	if i := strings.LastIndex(cut, " "); i > len(cut)/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:") + "…"
+}
+
+// summarizeWith runs any Summarizer and attaches provenance when it does not
+// report its own.
+func summarizeWith(ctx context.Context, summarizer Summarizer, posting JobPosting) (Summary, error) {
+	if attributed, ok := summarizer.(AttributedSummarizer); ok {
+		return attributed.SummarizeAttributed(ctx, posting)
+	}
+	text, err := summarizer.Summarize(ctx, posting)
+	if err != nil {
+		return Summary{}, err
+	}
+	return Summary{Text: text, Summarizer: fmt.Sprintf("%T", summarizer)}, nil
+}
@@ -0,0 +1,183 @@
+package broadcast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Golangjobsuz/golangjobsuz/internal/audit"
+)
+
+var (
+	// ErrNotPendingReview is returned when a review decision targets a
+	// broadcast that is not waiting in the moderation queue.
+	ErrNotPendingReview = errors.New("broadcast is not pending review")
+	// ErrReviewerRequired is returned when a review decision has no reviewer ID.
+	ErrReviewerRequired = errors.New("reviewer ID is required")
+)
+
+// Audit actions written for review decisions.
+const (
+	AuditApprove = "broadcast.approve"
+	AuditReject  = "broadcast.reject"
+	AuditEdit    = "broadcast.edit"
+)
+
+// WithModeration holds every new broadcast in StatusPendingReview until an
+// admin approves it. Review decisions are written to log with the reviewer ID.
+func (s *Service) WithModeration(log audit.Log) *Service {
+	s.audit = log
+	return s
+}
+
+// PendingReview lists the broadcasts waiting in the moderation queue.
+func (s *Service) PendingReview(ctx context.Context) ([]BroadcastRecord, error) {
+	records, err := s.repo.ListByStatus(ctx, StatusPendingReview)
+	if err != nil {
+		return nil, fmt.Errorf("list pending review: %w", err)
+	}
+	return records, nil
+}
+
+// ApproveBroadcast releases a broadcast from the moderation queue. It is
+// queued as StatusScheduled, keeping a future send time if one was requested,
+// and delivered by DispatchDue or a Dispatcher.
+func (s *Service) ApproveBroadcast(ctx context.Context, id, reviewerID string) (BroadcastRecord, error) {
+	record, err := s.pendingReview(ctx, id, reviewerID)
+	if err != nil {
+		return record, err
+	}
+
+	if err := s.auditReview(ctx, AuditApprove, reviewerID, record, nil); err != nil {
+		return record, err
+	}
+	record = s.recordChange(record, StatusScheduled)
+	if record.ScheduledAt == nil {
+		now := record.UpdatedAt
+		record.ScheduledAt = &now
+	}
+	if err := s.repo.Save(ctx, record); err != nil {
+		return record, fmt.Errorf("save approved record: %w", err)
+	}
+	return record, nil
+}
+
+// RejectBroadcast removes a broadcast from the moderation queue without
+// sending it.
+func (s *Service) RejectBroadcast(ctx context.Context, id, reviewerID, reason string) (BroadcastRecord, error) {
+	record, err := s.pendingReview(ctx, id, reviewerID)
+	if err != nil {
+		return record, err
+	}
+
+	if err := s.auditReview(ctx, AuditReject, reviewerID, record, map[string]any{"reason": reason}); err != nil {
+		return record, err
+	}
+	record = s.recordChange(record, StatusRejected)
+	if reason != "" {
+		record.Errors = append(record.Errors, "rejected: "+reason)
+	}
+	if err := s.repo.Save(ctx, record); err != nil {
+		return record, fmt.Errorf("save rejected record: %w", err)
+	}
+	return record, nil
+}
+
+// EditPendingBroadcast lets a reviewer correct a posting before approving it.
+// The broadcast stays in the moderation queue.
+func (s *Service) EditPendingBroadcast(ctx context.Context, id, reviewerID string, posting JobPosting) (BroadcastRecord, error) {
+	record, err := s.pendingReview(ctx, id, reviewerID)
+	if err != nil {
+		return record, err
+	}
+
+	if err := s.auditReview(ctx, AuditEdit, reviewerID, record, map[string]any{"previous": record.Job, "posting": posting}); err != nil {
+		return record, err
+	}
+	return s.EditBroadcast(ctx, id, posting)
+}
+
+// pendingReview loads a broadcast that a review decision may be applied to.
+func (s *Service) pendingReview(ctx context.Context, id, reviewerID string) (BroadcastRecord, error) {
+	if strings.TrimSpace(reviewerID) == "" {
+		return BroadcastRecord{}, ErrReviewerRequired
+	}
+	record, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return BroadcastRecord{}, fmt.Errorf("load broadcast %s: %w", id, err)
+	}
+	if record.Status != StatusPendingReview {
+		return record, fmt.Errorf("%w: broadcast %s is %s", ErrNotPendingReview, id, record.Status)
+	}
+	return record, nil
+}
+
+// auditReview writes a review decision to the audit log. It runs before the
+// decision is saved so no decision is applied without an audit entry.
+func (s *Service) auditReview(ctx context.Context, action, reviewerID string, record BroadcastRecord, metadata map[string]any) error {
+	if s.audit == nil {
+		return nil
+	}
+	if metadata == nil {
+		metadata = make(map[string]any)
+	}
+	metadata["channel"] = record.Channel
+	metadata["title"] = record.Job.Title
+	metadata["company"] = record.Job.Company
+
+	err := s.audit.Record(ctx, audit.Entry{
+		ActorID:    reviewerID,
+		Action:     action,
+		TargetType: "broadcast",
+		TargetID:   record.ID,
+		Metadata:   metadata,
+		CreatedAt:  s.clock(),
+	})
+	if err != nil {
+		return fmt.Errorf("audit %s of broadcast %s: %w", action, record.ID, err)
+	}
+	return nil
+}
+
+// WithFields returns a copy of the posting with the named fields replaced.
+// Field names are matched case-insensitively against title, company,
+// location, salary, experience, description, contact, apply_url and
+// expires_at (YYYY-MM-DD); it is used by the bot and CLI review commands.
+func (p JobPosting) WithFields(fields map[string]string) (JobPosting, error) {
+	for name, value := range fields {
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "title":
+			p.Title = value
+		case "company":
+			p.Company = value
+		case "location":
+			p.Location = value
+		case "salary":
+			p.Salary = value
+		case "experience":
+			p.Experience = value
+		case "description":
+			p.Description = value
+		case "contact":
+			p.Contact = value
+		case "apply_url":
+			p.ApplyURL = value
+		case "expires_at":
+			if value == "" {
+				p.ExpiresAt = time.Time{}
+				continue
+			}
+			expiresAt, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				return p, fmt.Errorf("invalid expires_at %q: want YYYY-MM-DD", value)
+			}
+			p.ExpiresAt = expiresAt
+		default:
+			return p, fmt.Errorf("unknown posting field %q", name)
+		}
+	}
+	return p, nil
+}
@@ -0,0 +1,263 @@
+package broadcast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresRepo stores broadcast records in the broadcasts table so several
+// processes can share the log.
+type PostgresRepo struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresRepo constructs a repository backed by the given pool.
+func NewPostgresRepo(pool *pgxpool.Pool) *PostgresRepo {
+	return &PostgresRepo{pool: pool}
+}
+
+const recordColumns = `record_id, job, fingerprint, duplicate_of, body, summary_by, summary_model, preview, channel_id, status, attempts, max_retries, errors, retry_waits, message_ids, dry_run, created_at, updated_at, scheduled_at, sent_at, reminder_sent_at, history`
+
+// Save inserts the record or updates the row with the same record ID.
+func (r *PostgresRepo) Save(ctx context.Context, record BroadcastRecord) error {
+	_, err := r.pool.Exec(ctx, `
+INSERT INTO broadcasts (record_id, title, job, fingerprint, duplicate_of, body, summary_by, summary_model, preview, channel_id, status, attempts, max_retries, errors, retry_waits, message_ids, dry_run, created_at, updated_at, scheduled_at, sent_at, reminder_sent_at, history)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23)
+ON CONFLICT (record_id) DO UPDATE SET
+    title = EXCLUDED.title,
+    job = EXCLUDED.job,
+    fingerprint = EXCLUDED.fingerprint,
+    duplicate_of = EXCLUDED.duplicate_of,
+    body = EXCLUDED.body,
+    summary_by = EXCLUDED.summary_by,
+    summary_model = EXCLUDED.summary_model,
+    preview = EXCLUDED.preview,
+    channel_id = EXCLUDED.channel_id,
+    status = EXCLUDED.status,
+    attempts = EXCLUDED.attempts,
+    max_retries = EXCLUDED.max_retries,
+    errors = EXCLUDED.errors,
+    retry_waits = EXCLUDED.retry_waits,
+    message_ids = EXCLUDED.message_ids,
+    dry_run = EXCLUDED.dry_run,
+    updated_at = EXCLUDED.updated_at,
+    scheduled_at = EXCLUDED.scheduled_at,
+    sent_at = EXCLUDED.sent_at,
+    reminder_sent_at = EXCLUDED.reminder_sent_at,
+    history = EXCLUDED.history`,
+		record.ID,
+		record.Job.Title,
+		record.Job,
+		record.Fingerprint,
+		record.DuplicateOf,
+		record.Summary,
+		record.SummaryBy,
+		record.SummaryModel,
+		record.Preview,
+		record.Channel,
+		string(record.Status),
+		record.Attempts,
+		record.MaxRetries,
+		nonNil(record.Errors),
+		nonNil(record.RetryWaits),
+		nonNil(record.MessageIDs),
+		record.DryRun,
+		record.CreatedAt,
+		record.UpdatedAt,
+		record.ScheduledAt,
+		record.LastSentAt,
+		record.ReminderSentAt,
+		nonNil(record.History),
+	)
+	if err != nil {
+		return fmt.Errorf("save broadcast %s: %w", record.ID, err)
+	}
+	return nil
+}
+
+// List retrieves all broadcast records ordered by creation time.
+func (r *PostgresRepo) List(ctx context.Context) ([]BroadcastRecord, error) {
+	return r.query(ctx, `SELECT `+recordColumns+` FROM broadcasts WHERE record_id IS NOT NULL ORDER BY created_at, id`)
+}
+
+// Get returns the record with the given ID or ErrNotFound.
+func (r *PostgresRepo) Get(ctx context.Context, id string) (BroadcastRecord, error) {
+	row := r.pool.QueryRow(ctx, `SELECT `+recordColumns+` FROM broadcasts WHERE record_id = $1`, id)
+	record, err := scanRecord(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return BroadcastRecord{}, ErrNotFound
+	}
+	if err != nil {
+		return BroadcastRecord{}, fmt.Errorf("get broadcast %s: %w", id, err)
+	}
+	return record, nil
+}
+
+// ListByStatus returns the records currently in the given status.
+func (r *PostgresRepo) ListByStatus(ctx context.Context, status RecordStatus) ([]BroadcastRecord, error) {
+	return r.query(ctx, `SELECT `+recordColumns+` FROM broadcasts WHERE record_id IS NOT NULL AND status = $1 ORDER BY created_at, id`, string(status))
+}
+
+// ListSince returns the records created at or after since.
+func (r *PostgresRepo) ListSince(ctx context.Context, since time.Time) ([]BroadcastRecord, error) {
+	return r.query(ctx, `SELECT `+recordColumns+` FROM broadcasts WHERE record_id IS NOT NULL AND created_at >= $1 ORDER BY created_at, id`, since)
+}
+
+// ListSentBetween returns the records last sent in [since, until).
+func (r *PostgresRepo) ListSentBetween(ctx context.Context, since, until time.Time) ([]BroadcastRecord, error) {
+	return r.query(ctx, `SELECT `+recordColumns+` FROM broadcasts WHERE record_id IS NOT NULL AND sent_at >= $1 AND sent_at < $2 ORDER BY sent_at, id`, since, until)
+}
+
+func (r *PostgresRepo) query(ctx context.Context, sql string, args ...any) ([]BroadcastRecord, error) {
+	rows, err := r.pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query broadcasts: %w", err)
+	}
+	defer rows.Close()
+
+	records := []BroadcastRecord{}
+	for rows.Next() {
+		record, err := scanRecord(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan broadcast: %w", err)
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read broadcasts: %w", err)
+	}
+	return records, nil
+}
+
+func scanRecord(row pgx.Row) (BroadcastRecord, error) {
+	var (
+		record  BroadcastRecord
+		status  string
+		channel *string
+	)
+	err := row.Scan(
+		&record.ID,
+		&record.Job,
+		&record.Fingerprint,
+		&record.DuplicateOf,
+		&record.Summary,
+		&record.SummaryBy,
+		&record.SummaryModel,
+		&record.Preview,
+		&channel,
+		&status,
+		&record.Attempts,
+		&record.MaxRetries,
+		&record.Errors,
+		&record.RetryWaits,
+		&record.MessageIDs,
+		&record.DryRun,
+		&record.CreatedAt,
+		&record.UpdatedAt,
+		&record.ScheduledAt,
+		&record.LastSentAt,
+		&record.ReminderSentAt,
+		&record.History,
+	)
+	if err != nil {
+		return BroadcastRecord{}, err
+	}
+	record.Status = RecordStatus(status)
+	if channel != nil {
+		record.Channel = *channel
+	}
+	return record, nil
+}
+
+// nonNil keeps NOT NULL JSONB columns from receiving SQL NULL for empty slices.
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+	return s
+}
+
+// Import copies every record from src into dst, typically to move the JSON
+// log kept by FileRepo into Postgres. Records are upserted by ID so running
+// it twice is harmless. It returns the number of records copied.
+func Import(ctx context.Context, src, dst Repo) (int, error) {
+	records, err := src.List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("list source records: %w", err)
+	}
+
+	for i, record := range records {
+		if err := dst.Save(ctx, record); err != nil {
+			return i, fmt.Errorf("import record %s: %w", record.ID, err)
+		}
+	}
+	return len(records), nil
+}
+
+// PostgresAnalytics stores click and view counts in the broadcast_stats table.
+type PostgresAnalytics struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresAnalytics constructs an analytics store backed by the given pool.
+func NewPostgresAnalytics(pool *pgxpool.Pool) *PostgresAnalytics {
+	return &PostgresAnalytics{pool: pool}
+}
+
+// AddClick increments the click counter of a broadcast.
+func (a *PostgresAnalytics) AddClick(ctx context.Context, recordID string) error {
+	_, err := a.pool.Exec(ctx, `
+INSERT INTO broadcast_stats (record_id, clicks, last_click_at)
+VALUES ($1, 1, NOW())
+ON CONFLICT (record_id) DO UPDATE SET
+    clicks = broadcast_stats.clicks + 1,
+    last_click_at = EXCLUDED.last_click_at`, recordID)
+	if err != nil {
+		return fmt.Errorf("add click for broadcast %s: %w", recordID, err)
+	}
+	return nil
+}
+
+// SetViews stores the latest view count of a broadcast.
+func (a *PostgresAnalytics) SetViews(ctx context.Context, recordID string, views int) error {
+	_, err := a.pool.Exec(ctx, `
+INSERT INTO broadcast_stats (record_id, views, views_updated_at)
+VALUES ($1, $2, NOW())
+ON CONFLICT (record_id) DO UPDATE SET
+    views = EXCLUDED.views,
+    views_updated_at = EXCLUDED.views_updated_at`, recordID, views)
+	if err != nil {
+		return fmt.Errorf("set views for broadcast %s: %w", recordID, err)
+	}
+	return nil
+}
+
+// Counters returns the counters of every tracked broadcast.
+func (a *PostgresAnalytics) Counters(ctx context.Context) (map[string]Counters, error) {
+	rows, err := a.pool.Query(ctx, `SELECT record_id, clicks, views FROM broadcast_stats`)
+	if err != nil {
+		return nil, fmt.Errorf("query broadcast stats: %w", err)
+	}
+	defer rows.Close()
+
+	counters := make(map[string]Counters)
+	for rows.Next() {
+		var (
+			id string
+			c  Counters
+		)
+		if err := rows.Scan(&id, &c.Clicks, &c.Views); err != nil {
+			return nil, fmt.Errorf("scan broadcast stats: %w", err)
+		}
+		counters[id] = c
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read broadcast stats: %w", err)
+	}
+	return counters, nil
+}
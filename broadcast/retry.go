@@ -0,0 +1,194 @@
+package broadcast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy decides how long to wait before another send attempt.
+type RetryPolicy interface {
+	// NextWait is called after the given 1-based attempt failed with err. It
+	// returns the delay before the next attempt and whether to retry at all.
+	NextWait(attempt int, err error) (time.Duration, bool)
+}
+
+// ErrorClass groups send errors by how a retry policy should treat them.
+type ErrorClass int
+
+const (
+	// ErrorRetryable covers transient failures such as timeouts or 5xx responses.
+	ErrorRetryable ErrorClass = iota
+	// ErrorRateLimited means the destination asked us to slow down.
+	ErrorRateLimited
+	// ErrorPermanent covers failures that will not succeed on retry.
+	ErrorPermanent
+)
+
+// Classifier maps a send error to an ErrorClass.
+type Classifier func(err error) ErrorClass
+
+// RateLimitError reports that the destination asked us to wait before the
+// next request, such as a Telegram 429 carrying retry_after.
+type RateLimitError struct {
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limited, retry after %s: %v", e.RetryAfter, e.Err)
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return e.Err
+}
+
+// PermanentError marks a send error that must not be retried.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
+// permanentMessages lists Telegram error fragments that never succeed on retry.
+var permanentMessages = []string{
+	"chat not found",
+	"bot was blocked",
+	"bot was kicked",
+	"user is deactivated",
+	"not enough rights",
+	"have no rights",
+	"unauthorized",
+	"message is too long",
+	"can't parse entities",
+}
+
+var retryAfterPattern = regexp.MustCompile(`(?i)retry after (\d+)`)
+
+// ClassifyError is the default Classifier. It understands RateLimitError and
+// PermanentError as well as the plain-text errors returned by the Telegram Bot API.
+func ClassifyError(err error) ErrorClass {
+	var rateLimit *RateLimitError
+	if errors.As(err, &rateLimit) {
+		return ErrorRateLimited
+	}
+	var permanent *PermanentError
+	if errors.As(err, &permanent) {
+		return ErrorPermanent
+	}
+
+	msg := strings.ToLower(err.Error())
+	if retryAfterPattern.MatchString(msg) || strings.Contains(msg, "too many requests") {
+		return ErrorRateLimited
+	}
+	for _, fragment := range permanentMessages {
+		if strings.Contains(msg, fragment) {
+			return ErrorPermanent
+		}
+	}
+	return ErrorRetryable
+}
+
+// retryAfter extracts the wait requested by a rate-limited destination.
+func retryAfter(err error) (time.Duration, bool) {
+	var rateLimit *RateLimitError
+	if errors.As(err, &rateLimit) {
+		return rateLimit.RetryAfter, true
+	}
+	match := retryAfterPattern.FindStringSubmatch(err.Error())
+	if match == nil {
+		return 0, false
+	}
+	seconds, convErr := strconv.Atoi(match[1])
+	if convErr != nil {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+// ExponentialBackoff doubles the wait after every failed attempt, applies
+// random jitter, and honours rate-limit hints from the destination.
+type ExponentialBackoff struct {
+	// Base is the wait after the first failed attempt.
+	Base time.Duration
+	// Max caps the computed wait. Rate-limit hints are honoured even above Max.
+	Max time.Duration
+	// Jitter is the fraction (0-1) of the wait that is randomly shaved off so
+	// parallel senders do not retry in lockstep.
+	Jitter float64
+	// Classify decides which errors are retried. Defaults to ClassifyError.
+	Classify Classifier
+
+	random func() float64
+}
+
+// DefaultRetryPolicy returns the policy used when none is configured.
+func DefaultRetryPolicy() *ExponentialBackoff {
+	return &ExponentialBackoff{
+		Base:     500 * time.Millisecond,
+		Max:      30 * time.Second,
+		Jitter:   0.2,
+		Classify: ClassifyError,
+	}
+}
+
+// NextWait implements RetryPolicy.
+func (p *ExponentialBackoff) NextWait(attempt int, err error) (time.Duration, bool) {
+	classify := p.Classify
+	if classify == nil {
+		classify = ClassifyError
+	}
+
+	switch classify(err) {
+	case ErrorPermanent:
+		return 0, false
+	case ErrorRateLimited:
+		if wait, ok := retryAfter(err); ok {
+			return wait, true
+		}
+	}
+
+	wait := p.Base
+	for i := 1; i < attempt && (p.Max <= 0 || wait < p.Max); i++ {
+		wait *= 2
+	}
+	if p.Max > 0 && wait > p.Max {
+		wait = p.Max
+	}
+
+	if p.Jitter > 0 {
+		random := p.random
+		if random == nil {
+			random = rand.Float64
+		}
+		wait -= time.Duration(float64(wait) * p.Jitter * random())
+	}
+	return wait, true
+}
+
+// sleepContext waits for d or until ctx is cancelled.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,173 @@
+package broadcast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoRoute is returned by FanOut when no route accepts a posting.
+var ErrNoRoute = errors.New("no channel route matches the posting")
+
+// CardFormatter renders a posting and its summary into channel text.
+// FormatCard is the default.
+type CardFormatter func(posting JobPosting, summary string) string
+
+// RouteRule reports whether a posting belongs in a channel.
+type RouteRule func(posting JobPosting) bool
+
+// Route sends postings accepted by Match to Channel, rendered with Format.
+// A nil Match accepts every posting and a nil Format uses FormatCard.
+type Route struct {
+	Channel string
+	Match   RouteRule
+	Format  CardFormatter
+}
+
+// WithRoutes configures the channels FanOut posts to. Each matching route
+// gets its own BroadcastRecord; routes sharing a channel are posted once,
+// using the first matching route.
+func (s *Service) WithRoutes(routes ...Route) *Service {
+	s.routes = routes
+	return s
+}
+
+// FanOut posts the posting to every channel whose route matches it, creating
+// one record per channel. A failure on one channel does not stop delivery to
+// the others: the records of all attempted channels are returned and the
+// errors are joined. The summary is generated once and shared by all
+// channels. Without configured routes the service's default channel is used.
+func (s *Service) FanOut(ctx context.Context, posting JobPosting, opts Options) ([]BroadcastRecord, error) {
+	routes := s.matchRoutes(posting)
+	if len(routes) == 0 {
+		return nil, ErrNoRoute
+	}
+
+	var (
+		summary    *Summary
+		summaryErr error
+	)
+	summarize := func(ctx context.Context) (Summary, error) {
+		if summary == nil && summaryErr == nil {
+			generated, err := summarizeWith(ctx, s.summarizer, posting)
+			summary, summaryErr = &generated, err
+		}
+		return *summary, summaryErr
+	}
+
+	var (
+		records []BroadcastRecord
+		errs    []error
+	)
+	for _, route := range routes {
+		record, err := s.postTo(ctx, posting, route.Channel, summarize, opts)
+		if record.ID != "" {
+			records = append(records, record)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("channel %s: %w", route.Channel, err))
+		}
+	}
+	return records, errors.Join(errs...)
+}
+
+// matchRoutes returns the routes that accept posting, one per channel.
+func (s *Service) matchRoutes(posting JobPosting) []Route {
+	if len(s.routes) == 0 {
+		return []Route{{Channel: s.channel}}
+	}
+
+	var matched []Route
+	seen := make(map[string]bool, len(s.routes))
+	for _, route := range s.routes {
+		if seen[route.Channel] || (route.Match != nil && !route.Match(posting)) {
+			continue
+		}
+		seen[route.Channel] = true
+		matched = append(matched, route)
+	}
+	return matched
+}
+
+// formatterFor returns the card formatter configured for channel.
+func (s *Service) formatterFor(channel string) CardFormatter {
+	for _, route := range s.routes {
+		if route.Channel == channel && route.Format != nil {
+			return route.Format
+		}
+	}
+	return FormatCard
+}
+
+// LocationMatches accepts postings whose location contains any of the
+// keywords, ignoring case.
+func LocationMatches(keywords ...string) RouteRule {
+	return func(posting JobPosting) bool {
+		return containsAny(posting.Location, keywords)
+	}
+}
+
+// ExperienceMatches accepts postings whose experience requirement contains
+// any of the keywords, ignoring case.
+func ExperienceMatches(keywords ...string) RouteRule {
+	return func(posting JobPosting) bool {
+		return containsAny(posting.Experience, keywords)
+	}
+}
+
+// TitleMatches accepts postings whose title contains any of the keywords,
+// ignoring case.
+func TitleMatches(keywords ...string) RouteRule {
+	return func(posting JobPosting) bool {
+		return containsAny(posting.Title, keywords)
+	}
+}
+
+// HasSalary accepts postings that state a salary.
+func HasSalary() RouteRule {
+	return func(posting JobPosting) bool {
+		return strings.TrimSpace(posting.Salary) != ""
+	}
+}
+
+// AllOf accepts postings matched by every rule.
+func AllOf(rules ...RouteRule) RouteRule {
+	return func(posting JobPosting) bool {
+		for _, rule := range rules {
+			if !rule(posting) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// AnyOf accepts postings matched by at least one rule.
+func AnyOf(rules ...RouteRule) RouteRule {
+	return func(posting JobPosting) bool {
+		for _, rule := range rules {
+			if rule(posting) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not inverts a rule.
+func Not(rule RouteRule) RouteRule {
+	return func(posting JobPosting) bool {
+		return !rule(posting)
+	}
+}
+
+func containsAny(text string, keywords []string) bool {
+	text = strings.ToLower(text)
+	for _, keyword := range keywords {
+		if keyword != "" && strings.Contains(text, strings.ToLower(keyword)) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,6 +2,8 @@ package broadcast
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,6 +12,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/Golangjobsuz/golangjobsuz/internal/audit"
 )
 
 // Sender posts formatted messages to a channel such as a vacancy feed.
@@ -17,6 +21,20 @@ type Sender interface {
 	Send(ctx context.Context, channel, message string) error
 }
 
+// MessageSender is implemented by senders that report the IDs of the messages
+// they posted, which lets a broadcast be edited or deleted later.
+type MessageSender interface {
+	SendMessages(ctx context.Context, channel, message string) ([]int, error)
+}
+
+// EditableSender is a Sender that can also change or remove messages it
+// posted earlier, identified by the IDs returned from MessageSender.
+type EditableSender interface {
+	Sender
+	EditMessages(ctx context.Context, channel string, messageIDs []int, message string) ([]int, error)
+	DeleteMessages(ctx context.Context, channel string, messageIDs []int) error
+}
+
 // Summarizer generates a concise description of a job posting.
 type Summarizer interface {
 	Summarize(ctx context.Context, posting JobPosting) (string, error)
@@ -31,52 +49,113 @@ type JobPosting struct {
 	Experience  string
 	Description string
 	Contact     string
+	// ApplyURL is where candidates apply. When empty, an apply link is
+	// derived from Contact; see ApplyTarget.
+	ApplyURL string
+	// ExpiresAt is when the vacancy closes; zero uses the expiry policy's
+	// default lifetime.
+	ExpiresAt time.Time
 }
 
 // RecordStatus represents the lifecycle state of a broadcast attempt.
 type RecordStatus string
 
 const (
-	StatusPending RecordStatus = "pending"
-	StatusSent    RecordStatus = "sent"
-	StatusFailed  RecordStatus = "failed"
-	StatusDryRun  RecordStatus = "dry-run"
+	StatusPending   RecordStatus = "pending"
+	StatusScheduled RecordStatus = "scheduled"
+	StatusSent      RecordStatus = "sent"
+	StatusFailed    RecordStatus = "failed"
+	StatusDryRun    RecordStatus = "dry-run"
+	StatusEdited    RecordStatus = "edited"
+	StatusRetracted RecordStatus = "retracted"
+	StatusDuplicate RecordStatus = "duplicate"
+	// StatusPendingReview marks broadcasts waiting for admin approval.
+	StatusPendingReview RecordStatus = "pending_review"
+	StatusRejected      RecordStatus = "rejected"
+	// StatusExpired marks vacancies closed automatically after an unanswered
+	// "still hiring?" reminder.
+	StatusExpired RecordStatus = "expired"
 )
 
 // BroadcastRecord tracks the attempts to deliver a broadcast.
 type BroadcastRecord struct {
-	ID         string       `json:"id"`
+	ID             string          `json:"id"`
+	Job            JobPosting      `json:"job"`
+	Fingerprint    string          `json:"fingerprint,omitempty"`
+	DuplicateOf    string          `json:"duplicateOf,omitempty"`
+	Summary        string          `json:"summary"`
+	SummaryBy      string          `json:"summaryBy,omitempty"`
+	SummaryModel   string          `json:"summaryModel,omitempty"`
+	Preview        string          `json:"preview,omitempty"`
+	Channel        string          `json:"channel"`
+	Status         RecordStatus    `json:"status"`
+	Attempts       int             `json:"attempts"`
+	MaxRetries     int             `json:"maxRetries,omitempty"`
+	Errors         []string        `json:"errors"`
+	RetryWaits     []time.Duration `json:"retryWaits,omitempty"`
+	MessageIDs     []int           `json:"messageIds,omitempty"`
+	DryRun         bool            `json:"dryRun"`
+	CreatedAt      time.Time       `json:"createdAt"`
+	UpdatedAt      time.Time       `json:"updatedAt"`
+	ScheduledAt    *time.Time      `json:"scheduledAt,omitempty"`
+	LastSentAt     *time.Time      `json:"lastSentAt,omitempty"`
+	ReminderSentAt *time.Time      `json:"reminderSentAt,omitempty"`
+	History        []RecordChange  `json:"history,omitempty"`
+}
+
+// RecordChange keeps the state a broadcast had before it was edited or
+// retracted.
+type RecordChange struct {
+	Status     RecordStatus `json:"status"`
 	Job        JobPosting   `json:"job"`
 	Summary    string       `json:"summary"`
-	Channel    string       `json:"channel"`
-	Status     RecordStatus `json:"status"`
-	Attempts   int          `json:"attempts"`
-	Errors     []string     `json:"errors"`
-	DryRun     bool         `json:"dryRun"`
-	CreatedAt  time.Time    `json:"createdAt"`
-	UpdatedAt  time.Time    `json:"updatedAt"`
-	LastSentAt *time.Time   `json:"lastSentAt,omitempty"`
+	MessageIDs []int        `json:"messageIds,omitempty"`
+	ChangedTo  RecordStatus `json:"changedTo"`
+	ChangedAt  time.Time    `json:"changedAt"`
 }
 
+// ErrNotFound is returned when a broadcast record does not exist.
+var ErrNotFound = errors.New("broadcast record not found")
+
 // Repo persists broadcast records for later inspection.
 type Repo interface {
 	Save(ctx context.Context, record BroadcastRecord) error
 	List(ctx context.Context) ([]BroadcastRecord, error)
+	Get(ctx context.Context, id string) (BroadcastRecord, error)
+	ListByStatus(ctx context.Context, status RecordStatus) ([]BroadcastRecord, error)
+	ListSince(ctx context.Context, since time.Time) ([]BroadcastRecord, error)
+	ListSentBetween(ctx context.Context, since, until time.Time) ([]BroadcastRecord, error)
 }
 
 // Options controls how broadcasts are delivered.
 type Options struct {
+	// DryRun saves the record with a rendered Preview of the card instead of
+	// sending it.
 	DryRun     bool
 	MaxRetries int
+	// SendAt schedules the broadcast for later delivery by a Dispatcher.
+	// A zero or past time sends immediately.
+	SendAt time.Time
+	// AllowDuplicate skips duplicate detection for intentional reposts.
+	AllowDuplicate bool
 }
 
 // Service coordinates formatting, sending, and tracking broadcasts.
 type Service struct {
-	sender     Sender
-	repo       Repo
-	summarizer Summarizer
-	channel    string
-	clock      func() time.Time
+	sender      Sender
+	repo        Repo
+	summarizer  Summarizer
+	channel     string
+	routes      []Route
+	retry       RetryPolicy
+	duplicates  DuplicatePolicy
+	audit       audit.Log
+	analytics   AnalyticsRepo
+	observer    AnalyticsObserver
+	trackingURL string
+	expiry      *ExpiryPolicy
+	clock       func() time.Time
+	sleep       func(ctx context.Context, d time.Duration) error
 }
 
 // NewService constructs a Service with sensible defaults.
@@ -86,49 +165,196 @@ func NewService(sender Sender, repo Repo, summarizer Summarizer, channel string)
 		repo:       repo,
 		summarizer: summarizer,
 		channel:    channel,
+		retry:      DefaultRetryPolicy(),
+		duplicates: DefaultDuplicatePolicy(),
 		clock:      time.Now,
+		sleep:      sleepContext,
 	}
 }
 
+// WithRetryPolicy replaces the policy used between failed send attempts.
+func (s *Service) WithRetryPolicy(policy RetryPolicy) *Service {
+	if policy != nil {
+		s.retry = policy
+	}
+	return s
+}
+
+// WithDuplicatePolicy replaces the duplicate detection settings. A zero
+// Threshold or Lookback keeps the default value.
+func (s *Service) WithDuplicatePolicy(policy DuplicatePolicy) *Service {
+	defaults := DefaultDuplicatePolicy()
+	if policy.Threshold <= 0 {
+		policy.Threshold = defaults.Threshold
+	}
+	if policy.Lookback <= 0 {
+		policy.Lookback = defaults.Lookback
+	}
+	s.duplicates = policy
+	return s
+}
+
 // PostBroadcast formats a card and posts it to the configured channel.
+// Postings that repeat a recent broadcast are rejected with a DuplicateError
+// or saved as StatusDuplicate, depending on the duplicate policy, unless
+// opts.AllowDuplicate is set. With moderation enabled the broadcast is saved
+// as StatusPendingReview and only sent once approved.
 func (s *Service) PostBroadcast(ctx context.Context, posting JobPosting, opts Options) (BroadcastRecord, error) {
+	summarize := func(ctx context.Context) (Summary, error) {
+		return summarizeWith(ctx, s.summarizer, posting)
+	}
+	return s.postTo(ctx, posting, s.channel, summarize, opts)
+}
+
+// postTo creates and delivers the record of a posting on one channel.
+// Duplicates are detected per channel, before summarize is called.
+func (s *Service) postTo(ctx context.Context, posting JobPosting, channel string, summarize func(context.Context) (Summary, error), opts Options) (BroadcastRecord, error) {
 	if opts.MaxRetries <= 0 {
 		opts.MaxRetries = 3
 	}
 
-	summary, err := s.summarizer.Summarize(ctx, posting)
+	var duplicate *DuplicateError
+	if !opts.AllowDuplicate {
+		found, err := s.findDuplicate(ctx, posting, channel)
+		if err != nil {
+			return BroadcastRecord{}, err
+		}
+		if found != nil && !opts.DryRun && s.duplicates.Action == DuplicateReject {
+			return BroadcastRecord{}, found
+		}
+		duplicate = found
+	}
+
+	summary, err := summarize(ctx)
 	if err != nil {
 		return BroadcastRecord{}, fmt.Errorf("summarize: %w", err)
 	}
 
-	card := FormatCard(posting, summary)
-
 	record := BroadcastRecord{
-		ID:        fmt.Sprintf("%d", s.clock().UnixNano()),
-		Job:       posting,
-		Summary:   summary,
-		Channel:   s.channel,
-		Status:    StatusPending,
-		CreatedAt: s.clock(),
-		UpdatedAt: s.clock(),
-		DryRun:    opts.DryRun,
+		ID:           recordID(),
+		Job:          posting,
+		Fingerprint:  Fingerprint(posting),
+		Summary:      summary.Text,
+		SummaryBy:    summary.Summarizer,
+		SummaryModel: summary.Model,
+		Channel:      channel,
+		Status:       StatusPending,
+		MaxRetries:   opts.MaxRetries,
+		CreatedAt:    s.clock(),
+		UpdatedAt:    s.clock(),
+		DryRun:       opts.DryRun,
+	}
+	if opts.SendAt.After(s.clock()) {
+		sendAt := opts.SendAt
+		record.ScheduledAt = &sendAt
+	}
+	if duplicate != nil {
+		record.DuplicateOf = duplicate.OriginalID
 	}
 
 	if opts.DryRun {
 		record.Status = StatusDryRun
+		record.Preview = s.renderCard(record)
 		if err := s.repo.Save(ctx, record); err != nil {
 			return record, fmt.Errorf("save dry-run record: %w", err)
 		}
 		return record, nil
 	}
 
-	for attempt := 1; attempt <= opts.MaxRetries; attempt++ {
+	if duplicate != nil {
+		record.Status = StatusDuplicate
+		if err := s.repo.Save(ctx, record); err != nil {
+			return record, fmt.Errorf("save duplicate record: %w", err)
+		}
+		return record, nil
+	}
+
+	if s.audit != nil {
+		record.Status = StatusPendingReview
+		if err := s.repo.Save(ctx, record); err != nil {
+			return record, fmt.Errorf("save pending review record: %w", err)
+		}
+		return record, nil
+	}
+
+	if record.ScheduledAt != nil {
+		record.Status = StatusScheduled
+		if err := s.repo.Save(ctx, record); err != nil {
+			return record, fmt.Errorf("save scheduled record: %w", err)
+		}
+		return record, nil
+	}
+
+	return s.deliver(ctx, record)
+}
+
+// Get returns the broadcast record with the given ID.
+func (s *Service) Get(ctx context.Context, id string) (BroadcastRecord, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// DispatchDue delivers every scheduled record whose send time has passed and
+// returns the records it processed. Delivery failures are recorded on each
+// record and joined into the returned error; the remaining records are still
+// attempted. Cancelling ctx stops dispatching and leaves unsent records
+// scheduled so they are picked up again later.
+func (s *Service) DispatchDue(ctx context.Context) ([]BroadcastRecord, error) {
+	scheduled, err := s.repo.ListByStatus(ctx, StatusScheduled)
+	if err != nil {
+		return nil, fmt.Errorf("list scheduled records: %w", err)
+	}
+
+	now := s.clock()
+	var (
+		processed []BroadcastRecord
+		errs      []error
+	)
+	for _, record := range scheduled {
+		if record.ScheduledAt != nil && record.ScheduledAt.After(now) {
+			continue
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		record, err := s.deliver(ctx, record)
+		if ctx.Err() != nil {
+			break
+		}
+		processed = append(processed, record)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("broadcast %s: %w", record.ID, err))
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return processed, err
+	}
+	return processed, errors.Join(errs...)
+}
+
+// deliver sends the record's card, retrying up to record.MaxRetries times as
+// allowed by the retry policy, and persists the outcome. A cancelled context
+// aborts delivery without saving so the record keeps its previous status.
+func (s *Service) deliver(ctx context.Context, record BroadcastRecord) (BroadcastRecord, error) {
+	maxRetries := record.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = 3
+	}
+	card := s.renderCard(record)
+
+	for attempt := 1; attempt <= maxRetries; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return record, err
+		}
+
 		record.Attempts = attempt
 		record.UpdatedAt = s.clock()
 
-		sendErr := s.sender.Send(ctx, s.channel, card)
+		messageIDs, sendErr := s.send(ctx, record.Channel, card)
 		if sendErr == nil {
 			now := s.clock()
+			record.MessageIDs = messageIDs
 			record.LastSentAt = &now
 			record.Status = StatusSent
 			if err := s.repo.Save(ctx, record); err != nil {
@@ -137,50 +363,67 @@ func (s *Service) PostBroadcast(ctx context.Context, posting JobPosting, opts Op
 			return record, nil
 		}
 
+		if ctx.Err() != nil {
+			return record, sendErr
+		}
+
 		record.Errors = append(record.Errors, sendErr.Error())
-		if attempt == opts.MaxRetries {
+		wait, retry := s.retry.NextWait(attempt, sendErr)
+		if !retry || attempt == maxRetries {
 			record.Status = StatusFailed
 			if err := s.repo.Save(ctx, record); err != nil {
 				return record, fmt.Errorf("save failed record: %w", err)
 			}
 			return record, sendErr
 		}
+
+		record.RetryWaits = append(record.RetryWaits, wait)
+		if err := s.sleep(ctx, wait); err != nil {
+			return record, err
+		}
 	}
 
 	return record, errors.New("unreachable")
 }
 
-// FormatCard builds the broadcast message using the AI summary plus key fields.
-func FormatCard(posting JobPosting, summary string) string {
-	var b strings.Builder
-	fmt.Fprintf(&b, "*%s* at *%s*\n", posting.Title, posting.Company)
-	if summary != "" {
-		fmt.Fprintf(&b, "%s\n\n", summary)
+// send posts the card, collecting message IDs when the sender reports them.
+func (s *Service) send(ctx context.Context, channel, card string) ([]int, error) {
+	if ms, ok := s.sender.(MessageSender); ok {
+		return ms.SendMessages(ctx, channel, card)
 	}
+	return nil, s.sender.Send(ctx, channel, card)
+}
 
-	if posting.Location != "" {
-		fmt.Fprintf(&b, "• Location: %s\n", posting.Location)
-	}
-	if posting.Salary != "" {
-		fmt.Fprintf(&b, "• Salary: %s\n", posting.Salary)
-	}
-	if posting.Experience != "" {
-		fmt.Fprintf(&b, "• Experience: %s\n", posting.Experience)
-	}
-	if posting.Description != "" {
-		fmt.Fprintf(&b, "• Details: %s\n", posting.Description)
-	}
-	if posting.Contact != "" {
-		fmt.Fprintf(&b, "• Contact: %s\n", posting.Contact)
-	}
+// FormatCard builds the broadcast message using the AI summary plus key fields,
+// rendered with the built-in English card template. The card is Telegram
+// MarkdownV2: field values are escaped so titles or company names containing
+// reserved characters do not break parsing.
+func FormatCard(posting JobPosting, summary string) string {
+	return defaultCard.Format(posting, summary)
+}
+
+var markdownV2Replacer = strings.NewReplacer(
+	"\\", "\\\\",
+	"_", "\\_", "*", "\\*", "[", "\\[", "]", "\\]", "(", "\\(", ")", "\\)",
+	"~", "\\~", "`", "\\`", ">", "\\>", "#", "\\#", "+", "\\+", "-", "\\-",
+	"=", "\\=", "|", "\\|", "{", "\\{", "}", "\\}", ".", "\\.", "!", "\\!",
+)
 
-	return strings.TrimSpace(b.String())
+// EscapeMarkdownV2 escapes the characters Telegram reserves in MarkdownV2 text.
+func EscapeMarkdownV2(text string) string {
+	return markdownV2Replacer.Replace(text)
 }
 
 // SimpleSummarizer provides a lightweight, deterministic summary for environments
 // without an LLM integration.
 type SimpleSummarizer struct{}
 
+// SummarizeAttributed implements AttributedSummarizer.
+func (s SimpleSummarizer) SummarizeAttributed(ctx context.Context, posting JobPosting) (Summary, error) {
+	text, err := s.Summarize(ctx, posting)
+	return Summary{Text: text, Summarizer: "simple"}, err
+}
+
 // Summarize condenses the description, preferring the first sentence or a trim.
 func (SimpleSummarizer) Summarize(_ context.Context, posting JobPosting) (string, error) {
 	desc := strings.TrimSpace(posting.Description)
@@ -241,6 +484,59 @@ func (r *FileRepo) List(_ context.Context) ([]BroadcastRecord, error) {
 	return r.readAll()
 }
 
+// Get returns the record with the given ID or ErrNotFound.
+func (r *FileRepo) Get(_ context.Context, id string) (BroadcastRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	records, err := r.readAll()
+	if err != nil {
+		return BroadcastRecord{}, err
+	}
+	for _, rec := range records {
+		if rec.ID == id {
+			return rec, nil
+		}
+	}
+	return BroadcastRecord{}, ErrNotFound
+}
+
+// ListByStatus returns the records currently in the given status.
+func (r *FileRepo) ListByStatus(_ context.Context, status RecordStatus) ([]BroadcastRecord, error) {
+	return r.filter(func(rec BroadcastRecord) bool { return rec.Status == status })
+}
+
+// ListSince returns the records created at or after since.
+func (r *FileRepo) ListSince(_ context.Context, since time.Time) ([]BroadcastRecord, error) {
+	return r.filter(func(rec BroadcastRecord) bool { return !rec.CreatedAt.Before(since) })
+}
+
+// ListSentBetween returns the records last sent in [since, until).
+func (r *FileRepo) ListSentBetween(_ context.Context, since, until time.Time) ([]BroadcastRecord, error) {
+	return r.filter(func(rec BroadcastRecord) bool { return sentBetween(rec, since, until) })
+}
+
+func sentBetween(rec BroadcastRecord, since, until time.Time) bool {
+	return rec.LastSentAt != nil && !rec.LastSentAt.Before(since) && rec.LastSentAt.Before(until)
+}
+
+func (r *FileRepo) filter(keep func(BroadcastRecord) bool) ([]BroadcastRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	records, err := r.readAll()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]BroadcastRecord, 0, len(records))
+	for _, rec := range records {
+		if keep(rec) {
+			out = append(out, rec)
+		}
+	}
+	return out, nil
+}
+
 func (r *FileRepo) readAll() ([]BroadcastRecord, error) {
 	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
 		return nil, err
@@ -274,3 +570,13 @@ func (r *FileRepo) writeAll(records []BroadcastRecord) error {
 
 	return os.WriteFile(r.path, data, 0o644)
 }
+
+// recordID returns a random broadcast record ID. Time-based IDs collide when
+// FanOut creates the records of several channels at once.
+func recordID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
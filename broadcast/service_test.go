@@ -3,8 +3,17 @@ package broadcast
 import (
 	"context"
 	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/Golangjobsuz/golangjobsuz/internal/audit"
 )
 
 type mockSender struct {
@@ -31,6 +40,12 @@ type stubRepo struct {
 }
 
 func (r *stubRepo) Save(_ context.Context, record BroadcastRecord) error {
+	for i := range r.saved {
+		if r.saved[i].ID == record.ID {
+			r.saved[i] = record
+			return nil
+		}
+	}
 	r.saved = append(r.saved, record)
 	return nil
 }
@@ -39,6 +54,45 @@ func (r *stubRepo) List(_ context.Context) ([]BroadcastRecord, error) {
 	return r.saved, nil
 }
 
+func (r *stubRepo) Get(_ context.Context, id string) (BroadcastRecord, error) {
+	for i := len(r.saved) - 1; i >= 0; i-- {
+		if r.saved[i].ID == id {
+			return r.saved[i], nil
+		}
+	}
+	return BroadcastRecord{}, ErrNotFound
+}
+
+func (r *stubRepo) ListByStatus(_ context.Context, status RecordStatus) ([]BroadcastRecord, error) {
+	var out []BroadcastRecord
+	for _, rec := range r.saved {
+		if rec.Status == status {
+			out = append(out, rec)
+		}
+	}
+	return out, nil
+}
+
+func (r *stubRepo) ListSince(_ context.Context, since time.Time) ([]BroadcastRecord, error) {
+	var out []BroadcastRecord
+	for _, rec := range r.saved {
+		if !rec.CreatedAt.Before(since) {
+			out = append(out, rec)
+		}
+	}
+	return out, nil
+}
+
+func (r *stubRepo) ListSentBetween(_ context.Context, since, until time.Time) ([]BroadcastRecord, error) {
+	var out []BroadcastRecord
+	for _, rec := range r.saved {
+		if sentBetween(rec, since, until) {
+			out = append(out, rec)
+		}
+	}
+	return out, nil
+}
+
 func TestFormatCardIncludesSummaryAndFields(t *testing.T) {
 	posting := JobPosting{
 		Title:       "Backend Engineer",
@@ -135,3 +189,1138 @@ func TestPostBroadcastSucceedsAfterRetry(t *testing.T) {
 		t.Fatalf("expected LastSentAt set")
 	}
 }
+
+func TestFileRepoQueries(t *testing.T) {
+	ctx := context.Background()
+	repo := NewFileRepo(filepath.Join(t.TempDir(), "broadcasts.json"))
+	base := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	for _, rec := range []BroadcastRecord{
+		{ID: "a", Status: StatusSent, CreatedAt: base},
+		{ID: "b", Status: StatusFailed, CreatedAt: base.Add(time.Hour)},
+		{ID: "c", Status: StatusSent, CreatedAt: base.Add(2 * time.Hour)},
+	} {
+		if err := repo.Save(ctx, rec); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+	}
+
+	got, err := repo.Get(ctx, "b")
+	if err != nil || got.Status != StatusFailed {
+		t.Fatalf("expected failed record b, got %+v (%v)", got, err)
+	}
+	if _, err := repo.Get(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	sent, err := repo.ListByStatus(ctx, StatusSent)
+	if err != nil {
+		t.Fatalf("list by status: %v", err)
+	}
+	if len(sent) != 2 {
+		t.Fatalf("expected 2 sent records, got %d", len(sent))
+	}
+
+	recent, err := repo.ListSince(ctx, base.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("list since: %v", err)
+	}
+	if len(recent) != 2 || recent[0].ID != "b" {
+		t.Fatalf("expected records b and c, got %+v", recent)
+	}
+}
+
+func TestImportCopiesAllRecords(t *testing.T) {
+	ctx := context.Background()
+	src := NewFileRepo(filepath.Join(t.TempDir(), "broadcasts.json"))
+	for _, id := range []string{"1", "2"} {
+		if err := src.Save(ctx, BroadcastRecord{ID: id, Status: StatusSent}); err != nil {
+			t.Fatalf("save: %v", err)
+		}
+	}
+
+	dst := &stubRepo{}
+	n, err := Import(ctx, src, dst)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if n != 2 || len(dst.saved) != 2 {
+		t.Fatalf("expected 2 records imported, got %d (%d saved)", n, len(dst.saved))
+	}
+}
+
+func TestPostBroadcastSchedulesFutureSend(t *testing.T) {
+	repo := &stubRepo{}
+	sender := &mockSender{}
+	svc := NewService(sender, repo, SimpleSummarizer{}, "#vacancies")
+	now := time.Date(2024, 5, 1, 6, 0, 0, 0, time.UTC)
+	svc.clock = func() time.Time { return now }
+
+	sendAt := now.Add(3 * time.Hour)
+	record, err := svc.PostBroadcast(context.Background(), JobPosting{Title: "Backend", Company: "ACME"}, Options{SendAt: sendAt, MaxRetries: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if record.Status != StatusScheduled {
+		t.Fatalf("expected scheduled status, got %s", record.Status)
+	}
+	if record.ScheduledAt == nil || !record.ScheduledAt.Equal(sendAt) {
+		t.Fatalf("expected ScheduledAt %s, got %v", sendAt, record.ScheduledAt)
+	}
+	if record.MaxRetries != 2 {
+		t.Fatalf("expected MaxRetries kept on record, got %d", record.MaxRetries)
+	}
+	if sender.calls != 0 {
+		t.Fatalf("expected no send before schedule, got %d", sender.calls)
+	}
+}
+
+func TestDispatchDueSendsOnlyDueRecords(t *testing.T) {
+	repo := &stubRepo{}
+	sender := &mockSender{failUntil: 1}
+	svc := NewService(sender, repo, SimpleSummarizer{}, "#vacancies")
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	svc.clock = func() time.Time { return now }
+
+	due := now.Add(-time.Minute)
+	later := now.Add(time.Hour)
+	repo.saved = []BroadcastRecord{
+		{ID: "due", Channel: "#vacancies", Job: JobPosting{Title: "Go"}, Status: StatusScheduled, ScheduledAt: &due, MaxRetries: 2},
+		{ID: "later", Channel: "#vacancies", Job: JobPosting{Title: "Rust"}, Status: StatusScheduled, ScheduledAt: &later},
+	}
+
+	processed, err := svc.DispatchDue(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(processed) != 1 || processed[0].ID != "due" {
+		t.Fatalf("expected only the due record processed, got %+v", processed)
+	}
+	if processed[0].Status != StatusSent || processed[0].Attempts != 2 {
+		t.Fatalf("expected sent after retry, got %s after %d attempts", processed[0].Status, processed[0].Attempts)
+	}
+	if sender.calls != 2 {
+		t.Fatalf("expected 2 send attempts, got %d", sender.calls)
+	}
+}
+
+func TestDispatcherStopsOnCancel(t *testing.T) {
+	repo := &stubRepo{}
+	svc := NewService(&mockSender{}, repo, SimpleSummarizer{}, "#vacancies")
+	dispatcher := NewDispatcher(svc, time.Millisecond, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- dispatcher.Run(ctx) }()
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("expected context.Canceled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("dispatcher did not stop after cancel")
+	}
+}
+
+type scriptedSender struct {
+	errs  []error
+	calls int
+}
+
+func (s *scriptedSender) Send(_ context.Context, _, _ string) error {
+	s.calls++
+	if s.calls <= len(s.errs) {
+		return s.errs[s.calls-1]
+	}
+	return nil
+}
+
+func TestExponentialBackoffNextWait(t *testing.T) {
+	policy := &ExponentialBackoff{Base: time.Second, Max: 5 * time.Second, random: func() float64 { return 0 }}
+	transient := errors.New("connection reset")
+
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second} {
+		got, retry := policy.NextWait(attempt, transient)
+		if !retry || got != want {
+			t.Fatalf("attempt %d: expected %s retry, got %s (retry=%v)", attempt, want, got, retry)
+		}
+	}
+
+	policy.Jitter = 0.5
+	policy.random = func() float64 { return 1 }
+	if got, _ := policy.NextWait(1, transient); got != 500*time.Millisecond {
+		t.Fatalf("expected jitter to shave half the wait, got %s", got)
+	}
+
+	if got, retry := policy.NextWait(1, errors.New("Too Many Requests: retry after 7")); !retry || got != 7*time.Second {
+		t.Fatalf("expected retry_after honoured, got %s (retry=%v)", got, retry)
+	}
+	if _, retry := policy.NextWait(1, errors.New("Bad Request: chat not found")); retry {
+		t.Fatalf("expected permanent error not retried")
+	}
+}
+
+func TestPostBroadcastStopsOnPermanentError(t *testing.T) {
+	repo := &stubRepo{}
+	sender := &scriptedSender{errs: []error{errors.New("Forbidden: bot was blocked by the user")}}
+	svc := NewService(sender, repo, SimpleSummarizer{}, "#vacancies")
+
+	record, err := svc.PostBroadcast(context.Background(), JobPosting{Title: "Backend"}, Options{MaxRetries: 5})
+	if err == nil {
+		t.Fatalf("expected permanent error returned")
+	}
+	if sender.calls != 1 {
+		t.Fatalf("expected a single attempt, got %d", sender.calls)
+	}
+	if record.Status != StatusFailed || len(record.RetryWaits) != 0 {
+		t.Fatalf("expected failed record without waits, got %s %v", record.Status, record.RetryWaits)
+	}
+}
+
+func TestPostBroadcastRecordsRetryWaits(t *testing.T) {
+	repo := &stubRepo{}
+	sender := &scriptedSender{errs: []error{
+		&RateLimitError{RetryAfter: 3 * time.Second, Err: errors.New("Too Many Requests")},
+		errors.New("gateway timeout"),
+	}}
+	svc := NewService(sender, repo, SimpleSummarizer{}, "#vacancies")
+	svc.WithRetryPolicy(&ExponentialBackoff{Base: time.Second, random: func() float64 { return 0 }})
+	var slept []time.Duration
+	svc.sleep = func(_ context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return nil
+	}
+
+	record, err := svc.PostBroadcast(context.Background(), JobPosting{Title: "Backend"}, Options{MaxRetries: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []time.Duration{3 * time.Second, 2 * time.Second}
+	if len(record.RetryWaits) != len(want) || record.RetryWaits[0] != want[0] || record.RetryWaits[1] != want[1] {
+		t.Fatalf("expected waits %v, got %v", want, record.RetryWaits)
+	}
+	if len(slept) != len(want) {
+		t.Fatalf("expected %d sleeps, got %v", len(want), slept)
+	}
+}
+
+type fakeTelegramAPI struct {
+	sent    []tgbotapi.MessageConfig
+	deleted []int
+	failOn  int
+	err     error
+}
+
+func (f *fakeTelegramAPI) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	msg := c.(tgbotapi.MessageConfig)
+	f.sent = append(f.sent, msg)
+	if len(f.sent) == f.failOn {
+		return tgbotapi.Message{}, f.err
+	}
+	return tgbotapi.Message{MessageID: 100 + len(f.sent)}, nil
+}
+
+func (f *fakeTelegramAPI) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	if del, ok := c.(tgbotapi.DeleteMessageConfig); ok {
+		f.deleted = append(f.deleted, del.MessageID)
+	}
+	return &tgbotapi.APIResponse{Ok: true}, nil
+}
+
+func TestFormatCardEscapesMarkdownV2(t *testing.T) {
+	card := FormatCard(JobPosting{Title: "Go_Dev", Company: "Acme Inc.", Salary: "$5k-$6k"}, "")
+
+	for _, expected := range []string{"*Go\\_Dev* at *Acme Inc\\.*", "Salary: $5k\\-$6k"} {
+		if !strings.Contains(card, expected) {
+			t.Fatalf("expected %q in card: %s", expected, card)
+		}
+	}
+}
+
+func TestSplitMessageRespectsLimitAndEscapes(t *testing.T) {
+	text := strings.Repeat("line of text\n", 10) + strings.Repeat("ab\\.", 10)
+	parts := SplitMessage(text, 20)
+	if len(parts) < 2 {
+		t.Fatalf("expected message split, got %d parts", len(parts))
+	}
+	for _, part := range parts {
+		if textLength(part) > 20 {
+			t.Fatalf("part over limit: %q", part)
+		}
+		if strings.HasSuffix(part, "\\") {
+			t.Fatalf("part ends inside an escape sequence: %q", part)
+		}
+	}
+	if joined := strings.Join(parts, ""); strings.ReplaceAll(joined, " ", "") != strings.ReplaceAll(strings.ReplaceAll(text, "\n", ""), " ", "") {
+		t.Fatalf("split lost content: %q", joined)
+	}
+
+	if got := SplitMessage("short", 20); len(got) != 1 || got[0] != "short" {
+		t.Fatalf("expected short message untouched, got %v", got)
+	}
+}
+
+func TestTelegramSenderReturnsMessageIDs(t *testing.T) {
+	api := &fakeTelegramAPI{}
+	sender := &TelegramSender{api: api, limit: 12}
+
+	ids, err := sender.SendMessages(context.Background(), "@golangjobsuz", "first line\nsecond line")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ids) != 2 || ids[0] != 101 || ids[1] != 102 {
+		t.Fatalf("expected ids [101 102], got %v", ids)
+	}
+	if api.sent[0].ChannelUsername != "@golangjobsuz" || api.sent[0].ParseMode != tgbotapi.ModeMarkdownV2 {
+		t.Fatalf("expected MarkdownV2 post to channel, got %+v", api.sent[0])
+	}
+}
+
+func TestTelegramSenderRollsBackPartialSend(t *testing.T) {
+	api := &fakeTelegramAPI{failOn: 2, err: &tgbotapi.Error{Code: 429, Message: "Too Many Requests", ResponseParameters: tgbotapi.ResponseParameters{RetryAfter: 4}}}
+	sender := &TelegramSender{api: api, limit: 12}
+
+	_, err := sender.SendMessages(context.Background(), "-100123", "first line\nsecond line")
+	var rateLimit *RateLimitError
+	if !errors.As(err, &rateLimit) || rateLimit.RetryAfter != 4*time.Second {
+		t.Fatalf("expected rate limit error with retry_after, got %v", err)
+	}
+	if len(api.deleted) != 1 || api.deleted[0] != 101 {
+		t.Fatalf("expected first part deleted, got %v", api.deleted)
+	}
+}
+
+func TestPostBroadcastStoresMessageIDs(t *testing.T) {
+	repo := &stubRepo{}
+	svc := NewService(&TelegramSender{api: &fakeTelegramAPI{}, limit: TelegramMessageLimit}, repo, SimpleSummarizer{}, "@golangjobsuz")
+
+	record, err := svc.PostBroadcast(context.Background(), JobPosting{Title: "Backend", Company: "ACME"}, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(record.MessageIDs) != 1 || record.MessageIDs[0] != 101 {
+		t.Fatalf("expected message id stored, got %v", record.MessageIDs)
+	}
+}
+
+type editableSender struct {
+	mockSender
+	edits   []string
+	deletes [][]int
+}
+
+func (e *editableSender) EditMessages(_ context.Context, _ string, messageIDs []int, message string) ([]int, error) {
+	e.edits = append(e.edits, message)
+	return messageIDs, nil
+}
+
+func (e *editableSender) DeleteMessages(_ context.Context, _ string, messageIDs []int) error {
+	e.deletes = append(e.deletes, messageIDs)
+	return nil
+}
+
+func TestEditBroadcastUpdatesPublishedPost(t *testing.T) {
+	repo := &stubRepo{saved: []BroadcastRecord{{ID: "b1", Channel: "@jobs", Job: JobPosting{Title: "Go Dev", Company: "ACME"}, Status: StatusSent, MessageIDs: []int{7}}}}
+	sender := &editableSender{}
+	svc := NewService(sender, repo, SimpleSummarizer{}, "@jobs")
+
+	record, err := svc.EditBroadcast(context.Background(), "b1", JobPosting{Title: "Senior Go Dev", Company: "ACME"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if record.Status != StatusEdited {
+		t.Fatalf("expected edited status, got %s", record.Status)
+	}
+	if len(sender.edits) != 1 || !strings.Contains(sender.edits[0], "Senior Go Dev") {
+		t.Fatalf("expected card re-rendered with new title, got %v", sender.edits)
+	}
+	if len(record.History) != 1 || record.History[0].Status != StatusSent || record.History[0].Job.Title != "Go Dev" {
+		t.Fatalf("expected previous state in history, got %+v", record.History)
+	}
+	if record.Fingerprint != Fingerprint(JobPosting{Title: "Senior Go Dev", Company: "ACME"}) {
+		t.Fatalf("expected the fingerprint of the edited posting, got %q", record.Fingerprint)
+	}
+}
+
+func TestRetractBroadcastMarksClosedOrDeletes(t *testing.T) {
+	repo := &stubRepo{saved: []BroadcastRecord{
+		{ID: "closed", Channel: "@jobs", Job: JobPosting{Title: "Go Dev"}, Status: StatusSent, MessageIDs: []int{7}},
+		{ID: "deleted", Channel: "@jobs", Job: JobPosting{Title: "QA"}, Status: StatusEdited, MessageIDs: []int{8, 9}},
+	}}
+	sender := &editableSender{}
+	observer := newFakeObserver()
+	svc := NewService(sender, repo, SimpleSummarizer{}, "@jobs").WithAnalytics(NewMemoryAnalytics(), observer)
+
+	record, err := svc.RetractBroadcast(context.Background(), "closed", true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record.Status != StatusRetracted || len(sender.edits) != 1 || !strings.Contains(sender.edits[0], "CLOSED") {
+		t.Fatalf("expected post edited with CLOSED banner, got %s %v", record.Status, sender.edits)
+	}
+
+	record, err = svc.RetractBroadcast(context.Background(), "deleted", false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sender.deletes) != 1 || len(sender.deletes[0]) != 2 || record.MessageIDs != nil {
+		t.Fatalf("expected both messages deleted, got %v (ids %v)", sender.deletes, record.MessageIDs)
+	}
+
+	if _, err := svc.RetractBroadcast(context.Background(), "deleted", false); !errors.Is(err, ErrRetracted) {
+		t.Fatalf("expected ErrRetracted on second retract, got %v", err)
+	}
+	if strings.Join(observer.forgotten, ",") != "closed,deleted" {
+		t.Fatalf("expected per-vacancy metrics dropped for both, got %v", observer.forgotten)
+	}
+}
+
+func TestEditBroadcastRequiresEditableSender(t *testing.T) {
+	repo := &stubRepo{saved: []BroadcastRecord{{ID: "b1", Status: StatusSent, MessageIDs: []int{7}}}}
+	svc := NewService(&mockSender{}, repo, SimpleSummarizer{}, "@jobs")
+
+	if _, err := svc.EditBroadcast(context.Background(), "b1", JobPosting{Title: "Go"}); !errors.Is(err, ErrNotEditable) {
+		t.Fatalf("expected ErrNotEditable, got %v", err)
+	}
+
+	repo.saved = append(repo.saved, BroadcastRecord{ID: "dry", Status: StatusDryRun})
+	if _, err := svc.RetractBroadcast(context.Background(), "dry", false); !errors.Is(err, ErrNotPublished) {
+		t.Fatalf("expected ErrNotPublished for dry run, got %v", err)
+	}
+}
+
+func TestTelegramSenderEditShrinksPost(t *testing.T) {
+	api := &fakeTelegramAPI{}
+	sender := &TelegramSender{api: api, limit: TelegramMessageLimit}
+
+	ids, err := sender.EditMessages(context.Background(), "@jobs", []int{5, 6}, "short card")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ids) != 1 || ids[0] != 5 {
+		t.Fatalf("expected only first message kept, got %v", ids)
+	}
+	if len(api.deleted) != 1 || api.deleted[0] != 6 {
+		t.Fatalf("expected surplus message deleted, got %v", api.deleted)
+	}
+}
+
+func TestFingerprintIgnoresFormatting(t *testing.T) {
+	a := JobPosting{Title: "Go Developer", Company: "ACME", Location: "Tashkent", Description: "Build APIs."}
+	b := JobPosting{Title: "  go developer!", Company: "acme", Location: "TASHKENT", Description: "build   apis"}
+	if Fingerprint(a) != Fingerprint(b) {
+		t.Fatalf("expected equal fingerprints for reformatted posting")
+	}
+	if Similarity(a, JobPosting{Title: "Designer", Company: "Other"}) >= DefaultDuplicatePolicy().Threshold {
+		t.Fatalf("expected unrelated postings below threshold")
+	}
+}
+
+func TestSimilarityIgnoresFieldsMissingFromBoth(t *testing.T) {
+	a := JobPosting{Title: "Go Developer", Company: "ACME"}
+	b := JobPosting{Title: "Senior Go Developer", Company: "ACME"}
+	if score := Similarity(a, b); score >= DefaultDuplicatePolicy().Threshold {
+		t.Fatalf("expected a different role at the same company below threshold, got %.2f", score)
+	}
+	if score := Similarity(a, JobPosting{Title: "Go Developer", Company: "ACME", Location: "Remote"}); score < DefaultDuplicatePolicy().Threshold {
+		t.Fatalf("expected the same role still matched when one side has a location, got %.2f", score)
+	}
+}
+
+func TestPostBroadcastRejectsDuplicate(t *testing.T) {
+	repo := &stubRepo{}
+	sender := &mockSender{}
+	svc := NewService(sender, repo, SimpleSummarizer{}, "#vacancies")
+	posting := JobPosting{Title: "Go Developer", Company: "ACME", Description: "Build APIs for payments"}
+
+	first, err := svc.PostBroadcast(context.Background(), posting, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	repost := posting
+	repost.Description = "Build APIs for payments!"
+	_, err = svc.PostBroadcast(context.Background(), repost, Options{})
+	var dup *DuplicateError
+	if !errors.As(err, &dup) || dup.OriginalID != first.ID {
+		t.Fatalf("expected duplicate of %s, got %v", first.ID, err)
+	}
+	if sender.calls != 1 {
+		t.Fatalf("expected duplicate not sent, got %d sends", sender.calls)
+	}
+
+	if _, err := svc.PostBroadcast(context.Background(), repost, Options{AllowDuplicate: true}); err != nil {
+		t.Fatalf("expected override to allow repost, got %v", err)
+	}
+}
+
+func TestPostBroadcastLinksDuplicate(t *testing.T) {
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	repo := &stubRepo{saved: []BroadcastRecord{
+		{ID: "old", Job: JobPosting{Title: "Go Developer", Company: "ACME"}, Channel: "#vacancies", Status: StatusSent, CreatedAt: now.AddDate(0, 0, -30)},
+		{ID: "recent", Job: JobPosting{Title: "Go Developer", Company: "ACME"}, Channel: "#vacancies", Status: StatusSent, CreatedAt: now.AddDate(0, 0, -2)},
+	}}
+	sender := &mockSender{}
+	svc := NewService(sender, repo, SimpleSummarizer{}, "#vacancies").
+		WithDuplicatePolicy(DuplicatePolicy{Lookback: 7 * 24 * time.Hour, Action: DuplicateLink})
+	svc.clock = func() time.Time { return now }
+
+	record, err := svc.PostBroadcast(context.Background(), JobPosting{Title: "Go developer", Company: "Acme"}, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record.Status != StatusDuplicate || record.DuplicateOf != "recent" {
+		t.Fatalf("expected link to recent broadcast, got %s -> %q", record.Status, record.DuplicateOf)
+	}
+	if sender.calls != 0 {
+		t.Fatalf("expected linked duplicate not sent")
+	}
+}
+
+type fakeCompleter struct {
+	text  string
+	model string
+	err   error
+	delay time.Duration
+}
+
+func (f fakeCompleter) Complete(ctx context.Context, _, _ string) (string, string, error) {
+	if f.delay > 0 {
+		select {
+		case <-time.After(f.delay):
+		case <-ctx.Done():
+			return "", "", ctx.Err()
+		}
+	}
+	return f.text, f.model, f.err
+}
+
+func TestLLMSummarizerTrimsToLimit(t *testing.T) {
+	summarizer := NewLLMSummarizer(fakeCompleter{text: "\"Go backend role building payment APIs with Postgres and Kafka for a fintech team\"", model: "gpt-4o-mini"}, "openai", 40, time.Second, nil)
+
+	summary, err := summarizer.SummarizeAttributed(context.Background(), JobPosting{Title: "Go Dev"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len([]rune(summary.Text)) > 40 || strings.HasPrefix(summary.Text, "\"") {
+		t.Fatalf("expected trimmed unquoted summary, got %q", summary.Text)
+	}
+	if summary.Summarizer != "openai" || summary.Model != "gpt-4o-mini" {
+		t.Fatalf("expected provenance recorded, got %+v", summary)
+	}
+}
+
+func TestLLMSummarizerFallsBack(t *testing.T) {
+	posting := JobPosting{Title: "Go Dev", Description: "Build APIs. Join the team."}
+	for name, client := range map[string]fakeCompleter{
+		"error":   {err: errors.New("upstream 500")},
+		"empty":   {text: "  "},
+		"timeout": {text: "late", delay: time.Second},
+	} {
+		summarizer := NewLLMSummarizer(client, "gemini", 0, 10*time.Millisecond, nil)
+		summary, err := summarizer.SummarizeAttributed(context.Background(), posting)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", name, err)
+		}
+		if summary.Summarizer != "simple" || summary.Text != "Go Dev — Build APIs" {
+			t.Fatalf("%s: expected simple fallback, got %+v", name, summary)
+		}
+	}
+}
+
+func TestPostBroadcastRecordsSummarizer(t *testing.T) {
+	repo := &stubRepo{}
+	summarizer := NewLLMSummarizer(fakeCompleter{text: "Remote Go role.", model: "gemini-1.5-flash"}, "gemini", 0, time.Second, nil)
+	svc := NewService(&mockSender{}, repo, summarizer, "#vacancies")
+
+	record, err := svc.PostBroadcast(context.Background(), JobPosting{Title: "Go Dev", Company: "ACME"}, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record.Summary != "Remote Go role." || record.SummaryBy != "gemini" || record.SummaryModel != "gemini-1.5-flash" {
+		t.Fatalf("expected LLM summary provenance, got %q by %q/%q", record.Summary, record.SummaryBy, record.SummaryModel)
+	}
+}
+
+type channelSender struct {
+	failing  map[string]bool
+	messages map[string][]string
+}
+
+func (c *channelSender) Send(_ context.Context, channel, message string) error {
+	if c.failing[channel] {
+		return &PermanentError{Err: errors.New("chat not found")}
+	}
+	if c.messages == nil {
+		c.messages = make(map[string][]string)
+	}
+	c.messages[channel] = append(c.messages[channel], message)
+	return nil
+}
+
+func TestFanOutRoutesPerChannel(t *testing.T) {
+	repo := &stubRepo{}
+	sender := &channelSender{failing: map[string]bool{"@remote": true}}
+	svc := NewService(sender, repo, SimpleSummarizer{}, "#vacancies").WithRoutes(
+		Route{Channel: "@remote", Match: LocationMatches("remote", "masofaviy")},
+		Route{Channel: "@tashkent", Match: AllOf(LocationMatches("tashkent"), HasSalary()), Format: func(p JobPosting, _ string) string {
+			return "Toshkent: " + p.Title
+		}},
+		Route{Channel: "@interns", Match: AnyOf(TitleMatches("intern"), ExperienceMatches("no experience"))},
+	)
+	// A coarse clock must not make the records of one fan-out collide.
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	svc.clock = func() time.Time { return now }
+
+	posting := JobPosting{Title: "Go Developer", Company: "ACME", Location: "Tashkent or Remote", Salary: "$3k"}
+	records, err := svc.FanOut(context.Background(), posting, Options{})
+	if err == nil || !strings.Contains(err.Error(), "@remote") {
+		t.Fatalf("expected @remote failure reported, got %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("expected a record per matching channel, got %d", len(records))
+	}
+	if records[0].Channel != "@remote" || records[0].Status != StatusFailed {
+		t.Fatalf("expected failed @remote record, got %s %s", records[0].Channel, records[0].Status)
+	}
+	if records[0].ID == records[1].ID {
+		t.Fatalf("expected distinct record IDs per channel, got %q twice", records[0].ID)
+	}
+	if records[1].Channel != "@tashkent" || records[1].Status != StatusSent {
+		t.Fatalf("expected @tashkent delivered despite @remote failure, got %s %s", records[1].Channel, records[1].Status)
+	}
+	if got := sender.messages["@tashkent"]; len(got) != 1 || got[0] != "Toshkent: Go Developer" {
+		t.Fatalf("expected channel template used, got %v", got)
+	}
+	if len(sender.messages["@interns"]) != 0 {
+		t.Fatalf("expected internship channel skipped")
+	}
+
+	if _, err := svc.FanOut(context.Background(), JobPosting{Title: "Go Developer", Location: "Samarkand"}, Options{}); !errors.Is(err, ErrNoRoute) {
+		t.Fatalf("expected ErrNoRoute, got %v", err)
+	}
+}
+
+func TestFanOutDetectsDuplicatesPerChannel(t *testing.T) {
+	repo := &stubRepo{}
+	svc := NewService(&channelSender{}, repo, SimpleSummarizer{}, "#vacancies").WithRoutes(
+		Route{Channel: "@all"},
+		Route{Channel: "@remote", Match: Not(LocationMatches("office"))},
+	)
+	posting := JobPosting{Title: "Go Developer", Company: "ACME", Location: "Remote"}
+
+	records, err := svc.FanOut(context.Background(), posting, Options{})
+	if err != nil || len(records) != 2 {
+		t.Fatalf("expected both channels posted, got %d records, err %v", len(records), err)
+	}
+
+	if _, err := svc.PostBroadcast(context.Background(), posting, Options{}); err != nil {
+		t.Fatalf("expected default channel unaffected by routed posts, got %v", err)
+	}
+	if _, err := svc.FanOut(context.Background(), posting, Options{}); !errors.Is(err, ErrDuplicate) {
+		t.Fatalf("expected repost rejected on routed channels, got %v", err)
+	}
+}
+
+func TestBuiltinCardTemplatesRender(t *testing.T) {
+	posting := JobPosting{Title: "Go_Dev", Company: "Acme", Salary: "$5k", Location: "Toshkent"}
+	labels := map[string]string{"en": "• Salary: $5k", "ru": "• Зарплата: $5k", "uz": "• Maosh: $5k"}
+	for _, lang := range CardLanguages {
+		card, err := BuiltinCardTemplate(lang)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", lang, err)
+		}
+		out := card.Format(posting, "")
+		if !strings.HasPrefix(out, "*Go\\_Dev*") || !strings.Contains(out, labels[lang]) {
+			t.Fatalf("%s: unexpected card %q", lang, out)
+		}
+	}
+	if _, err := BuiltinCardTemplate("de"); err == nil {
+		t.Fatalf("expected unknown language rejected")
+	}
+}
+
+func TestParseCardTemplateValidates(t *testing.T) {
+	for name, text := range map[string]string{
+		"syntax":     "*{{.Title}*",
+		"field":      "{{.Salary.Amount}}",
+		"empty":      "{{if false}}x{{end}}",
+		"reserved":   "{{.Title}} - {{.Company}}",
+		"unbalanced": "*{{.Title}}",
+	} {
+		if _, err := ParseCardTemplate(name, text); err == nil {
+			t.Fatalf("%s: expected template rejected", name)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "remote.tmpl")
+	if err := os.WriteFile(path, []byte("🌍 *{{.Title}}* \\- [apply](https://t.me/golangjobsuz)\n{{with .Salary}}💰 {{.}}{{end}}"), 0o644); err != nil {
+		t.Fatalf("write template: %v", err)
+	}
+	card, err := LoadCardTemplate(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if card.Name() != "remote" {
+		t.Fatalf("expected name from file, got %q", card.Name())
+	}
+}
+
+func TestDryRunRendersChannelPreview(t *testing.T) {
+	card, err := ParseCardTemplate("short", "{{.Title}} \\| {{.Company}}")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repo := &stubRepo{}
+	sender := &channelSender{}
+	svc := NewService(sender, repo, SimpleSummarizer{}, "#vacancies").WithRoutes(Route{Channel: "@short", Format: card.Format})
+
+	records, err := svc.FanOut(context.Background(), JobPosting{Title: "Go Dev", Company: "ACME"}, Options{DryRun: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if records[0].Preview != "Go Dev \\| ACME" || len(sender.messages) != 0 {
+		t.Fatalf("expected preview without sending, got %q", records[0].Preview)
+	}
+}
+
+func TestModerationQueueApproveAndReject(t *testing.T) {
+	repo := &stubRepo{}
+	sender := &mockSender{}
+	log := audit.NewMemoryLog()
+	svc := NewService(sender, repo, SimpleSummarizer{}, "#vacancies").WithModeration(log)
+	ctx := context.Background()
+
+	first, err := svc.PostBroadcast(ctx, JobPosting{Title: "Go Dev", Company: "ACME"}, Options{})
+	if err != nil || first.Status != StatusPendingReview {
+		t.Fatalf("expected pending review, got %s (%v)", first.Status, err)
+	}
+	second, _ := svc.PostBroadcast(ctx, JobPosting{Title: "Rust Dev", Company: "Other"}, Options{})
+	if sender.calls != 0 {
+		t.Fatalf("expected nothing sent before approval")
+	}
+	if pending, _ := svc.PendingReview(ctx); len(pending) != 2 {
+		t.Fatalf("expected two records in queue, got %d", len(pending))
+	}
+
+	if _, err := svc.ApproveBroadcast(ctx, first.ID, ""); !errors.Is(err, ErrReviewerRequired) {
+		t.Fatalf("expected reviewer required, got %v", err)
+	}
+	edited, err := svc.EditPendingBroadcast(ctx, first.ID, "42", JobPosting{Title: "Go Developer", Company: "ACME"})
+	if err != nil || edited.Status != StatusPendingReview || edited.Job.Title != "Go Developer" {
+		t.Fatalf("expected edit kept in queue, got %+v (%v)", edited, err)
+	}
+	if _, err := svc.ApproveBroadcast(ctx, first.ID, "42"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.RejectBroadcast(ctx, second.ID, "7", "spam"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.ApproveBroadcast(ctx, second.ID, "42"); !errors.Is(err, ErrNotPendingReview) {
+		t.Fatalf("expected rejected broadcast not approvable, got %v", err)
+	}
+
+	sent, err := svc.DispatchDue(ctx)
+	if err != nil || len(sent) != 1 || sent[0].ID != first.ID || sent[0].Status != StatusSent {
+		t.Fatalf("expected only approved broadcast sent, got %+v (%v)", sent, err)
+	}
+
+	entries := log.Entries()
+	want := []string{AuditEdit, AuditApprove, AuditReject}
+	if len(entries) != len(want) {
+		t.Fatalf("expected %d audit entries, got %d", len(want), len(entries))
+	}
+	for i, entry := range entries {
+		if entry.Action != want[i] || entry.TargetType != "broadcast" || entry.ActorID == "" {
+			t.Fatalf("unexpected audit entry %d: %+v", i, entry)
+		}
+	}
+	if entries[2].ActorID != "7" || entries[2].Metadata["reason"] != "spam" {
+		t.Fatalf("expected reject reason and reviewer recorded, got %+v", entries[2])
+	}
+}
+
+func TestJobPostingWithFields(t *testing.T) {
+	posting, err := JobPosting{Title: "Go Dev", Salary: "$1k"}.WithFields(map[string]string{"Salary": " $2k ", "location": "Remote"})
+	if err != nil || posting.Salary != "$2k" || posting.Location != "Remote" || posting.Title != "Go Dev" {
+		t.Fatalf("unexpected posting %+v (%v)", posting, err)
+	}
+	if _, err := (JobPosting{}).WithFields(map[string]string{"bonus": "x"}); err == nil {
+		t.Fatalf("expected unknown field rejected")
+	}
+}
+
+type viewSender struct {
+	mockSender
+	views map[int]int
+}
+
+func (v *viewSender) SendMessages(ctx context.Context, channel, message string) ([]int, error) {
+	if err := v.Send(ctx, channel, message); err != nil {
+		return nil, err
+	}
+	return []int{v.calls}, nil
+}
+
+func (v *viewSender) Views(_ context.Context, _ string, messageIDs []int) (int, error) {
+	total := 0
+	for _, id := range messageIDs {
+		total += v.views[id]
+	}
+	return total, nil
+}
+
+type fakeObserver struct {
+	clicks        map[string]int
+	views         map[string]int
+	vacancyClicks map[string]int
+	vacancyViews  map[string]int
+	forgotten     []string
+}
+
+func newFakeObserver() *fakeObserver {
+	return &fakeObserver{clicks: map[string]int{}, views: map[string]int{}, vacancyClicks: map[string]int{}, vacancyViews: map[string]int{}}
+}
+
+func (f *fakeObserver) ObserveClick(channel, company string) {
+	f.clicks[channel+"/"+company]++
+}
+
+func (f *fakeObserver) ObserveViews(channel, company string, views int) {
+	f.views[channel+"/"+company] = views
+}
+
+func (f *fakeObserver) ObserveVacancyClick(recordID string) {
+	f.vacancyClicks[recordID]++
+}
+
+func (f *fakeObserver) ObserveVacancyViews(recordID string, views int) {
+	f.vacancyViews[recordID] = views
+}
+
+func (f *fakeObserver) ForgetVacancy(recordID string) {
+	f.forgotten = append(f.forgotten, recordID)
+}
+
+func TestApplyTarget(t *testing.T) {
+	for contact, want := range map[string]string{
+		"https://acme.uz/jobs/1": "https://acme.uz/jobs/1",
+		"@acme_hr":               "https://t.me/acme_hr",
+		"hr@acme.uz":             "mailto:hr@acme.uz",
+		"+998 90 123 45 67":      "",
+	} {
+		if got := ApplyTarget(JobPosting{Contact: contact}); got != want {
+			t.Fatalf("%s: expected %q, got %q", contact, want, got)
+		}
+	}
+	if got := ApplyTarget(JobPosting{Contact: "@acme_hr", ApplyURL: "https://acme.uz/apply"}); got != "https://acme.uz/apply" {
+		t.Fatalf("expected ApplyURL preferred, got %q", got)
+	}
+}
+
+func TestApplyHandlerTracksClicks(t *testing.T) {
+	repo := &stubRepo{}
+	sender := &viewSender{views: map[int]int{1: 120}}
+	analytics := NewMemoryAnalytics()
+	observer := newFakeObserver()
+	svc := NewService(sender, repo, SimpleSummarizer{}, "@jobs").
+		WithAnalytics(analytics, observer).
+		WithApplyTracking("https://golangjobs.uz/")
+	ctx := context.Background()
+
+	record, err := svc.PostBroadcast(ctx, JobPosting{Title: "Go Dev", Company: "ACME", Contact: "@acme_hr"}, Options{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.PostBroadcast(ctx, JobPosting{Title: "Rust Dev", Company: "ACME"}, Options{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		svc.ApplyHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/apply/"+record.ID, nil))
+		if rec.Code != http.StatusFound || rec.Header().Get("Location") != "https://t.me/acme_hr" {
+			t.Fatalf("expected redirect to contact, got %d %q", rec.Code, rec.Header().Get("Location"))
+		}
+	}
+	rec := httptest.NewRecorder()
+	svc.ApplyHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/apply/unknown", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown broadcast, got %d", rec.Code)
+	}
+
+	if n, err := svc.CollectViews(ctx, time.Time{}); err != nil || n != 2 {
+		t.Fatalf("expected views collected for both posts, got %d (%v)", n, err)
+	}
+
+	vacancies, err := svc.VacancyStats(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(vacancies) != 2 || vacancies[0].RecordID != record.ID || vacancies[0].Clicks != 2 || vacancies[0].Views != 120 {
+		t.Fatalf("unexpected vacancy stats %+v", vacancies)
+	}
+	companies, err := svc.CompanyStats(ctx)
+	if err != nil || len(companies) != 1 || companies[0].Broadcasts != 2 || companies[0].Clicks != 2 {
+		t.Fatalf("unexpected company stats %+v (%v)", companies, err)
+	}
+	if observer.clicks["@jobs/ACME"] != 2 || observer.views["@jobs/ACME"] != 120 {
+		t.Fatalf("expected observer updated, got %+v", observer)
+	}
+	if observer.vacancyClicks[record.ID] != 2 || observer.vacancyViews[record.ID] != 120 {
+		t.Fatalf("expected per-vacancy observer updates, got %+v", observer)
+	}
+}
+
+func TestTrackedApplyLinkInCard(t *testing.T) {
+	repo := &stubRepo{}
+	svc := NewService(&mockSender{}, repo, SimpleSummarizer{}, "@jobs").WithApplyTracking("https://golangjobs.uz")
+
+	record, err := svc.PostBroadcast(context.Background(), JobPosting{Title: "Go Dev", Contact: "hr@acme.uz"}, Options{DryRun: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(record.Preview, "[Apply](https://golangjobs.uz/apply/"+record.ID+")") {
+		t.Fatalf("expected tracked apply link, got %q", record.Preview)
+	}
+
+	if _, err := svc.CollectViews(context.Background(), time.Time{}); !errors.Is(err, ErrViewsUnsupported) {
+		t.Fatalf("expected unsupported views, got %v", err)
+	}
+}
+
+type reminderNotifier struct {
+	seekers     []string
+	admins      []string
+	unreachable bool
+}
+
+func (r *reminderNotifier) NotifySeeker(_ context.Context, seekerContact, message string) error {
+	if r.unreachable {
+		return errors.New("chat not found")
+	}
+	r.seekers = append(r.seekers, seekerContact+": "+message)
+	return nil
+}
+
+func (r *reminderNotifier) NotifyAdmin(_ context.Context, message string) error {
+	r.admins = append(r.admins, message)
+	return nil
+}
+
+func TestCheckExpiryRemindsThenCloses(t *testing.T) {
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	sent := now.AddDate(0, 0, -9)
+	repo := &stubRepo{saved: []BroadcastRecord{
+		{ID: "open", Job: JobPosting{Title: "Go Dev", Contact: "@acme_hr"}, Channel: "@jobs", Status: StatusSent, MessageIDs: []int{7}, CreatedAt: sent, LastSentAt: &sent},
+		{ID: "dated", Job: JobPosting{Title: "QA", ExpiresAt: now.AddDate(0, 1, 0)}, Channel: "@jobs", Status: StatusSent, MessageIDs: []int{8}, CreatedAt: sent, LastSentAt: &sent},
+	}}
+	sender := &editableSender{}
+	notifier := &reminderNotifier{}
+	svc := NewService(sender, repo, SimpleSummarizer{}, "@jobs").WithExpiryPolicy(ExpiryPolicy{
+		DefaultLifetime: 10 * 24 * time.Hour,
+		MarkClosed:      true,
+		Reminders:       notifier,
+	})
+	svc.clock = func() time.Time { return now }
+	ctx := context.Background()
+
+	changed, err := svc.CheckExpiry(ctx)
+	if err != nil || len(changed) != 1 || changed[0].ReminderSentAt == nil {
+		t.Fatalf("expected one reminder, got %+v (%v)", changed, err)
+	}
+	if len(notifier.seekers) != 1 || !strings.Contains(notifier.seekers[0], "@acme_hr: ") || !strings.Contains(notifier.seekers[0], "/still_hiring open") {
+		t.Fatalf("expected reminder to the contact, got %v", notifier.seekers)
+	}
+	if changed, _ := svc.CheckExpiry(ctx); len(changed) != 0 {
+		t.Fatalf("expected reminder sent only once, got %d changes", len(changed))
+	}
+
+	now = now.Add(24 * time.Hour)
+	if changed, _ := svc.CheckExpiry(ctx); len(changed) != 0 {
+		t.Fatalf("expected answer window respected, got %d changes", len(changed))
+	}
+
+	now = now.Add(24 * time.Hour)
+	changed, err = svc.CheckExpiry(ctx)
+	if err != nil || len(changed) != 1 || changed[0].Status != StatusExpired {
+		t.Fatalf("expected unanswered vacancy expired, got %+v (%v)", changed, err)
+	}
+	if len(sender.edits) != 1 || !strings.HasPrefix(sender.edits[0], closedBanner) {
+		t.Fatalf("expected post marked closed, got %v", sender.edits)
+	}
+	last := changed[0].History[len(changed[0].History)-1]
+	if last.Status != StatusSent || last.ChangedTo != StatusExpired {
+		t.Fatalf("expected status change recorded, got %+v", last)
+	}
+}
+
+func TestCheckExpiryFallsBackWhenContactIsUnreachable(t *testing.T) {
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	sent := now.AddDate(0, 0, -29)
+	repo := &stubRepo{saved: []BroadcastRecord{
+		{ID: "mail", Job: JobPosting{Title: "Go Dev", Contact: "hr@acme.uz"}, Channel: "@jobs", Status: StatusSent, MessageIDs: []int{7}, CreatedAt: sent, LastSentAt: &sent},
+	}}
+	sender := &editableSender{}
+	notifier := &reminderNotifier{unreachable: true}
+	policy := DefaultExpiryPolicy()
+	policy.Reminders = notifier
+	svc := NewService(sender, repo, SimpleSummarizer{}, "@jobs").WithExpiryPolicy(policy)
+	svc.clock = func() time.Time { return now }
+
+	changed, err := svc.CheckExpiry(context.Background())
+	if err != nil || len(changed) != 1 || changed[0].ReminderSentAt == nil {
+		t.Fatalf("expected the reminder recorded, got %+v (%v)", changed, err)
+	}
+	if len(notifier.admins) != 1 || !strings.Contains(notifier.admins[0], "hr@acme.uz") || sender.calls != 0 {
+		t.Fatalf("expected the reminder relayed to the admins only, got %v", notifier.admins)
+	}
+
+	// Without any notifier the vacancy still expires after the answer window.
+	svc.expiry.Reminders = nil
+	repo.saved[0].ReminderSentAt = nil
+	changed, err = svc.CheckExpiry(context.Background())
+	if err != nil || len(changed) != 1 || len(changed[0].Errors) != 1 {
+		t.Fatalf("expected the undelivered reminder noted, got %+v (%v)", changed, err)
+	}
+	now = now.AddDate(0, 0, 3)
+	if changed, err := svc.CheckExpiry(context.Background()); err != nil || len(changed) != 1 || changed[0].Status != StatusExpired {
+		t.Fatalf("expected the vacancy expired, got %+v (%v)", changed, err)
+	}
+}
+
+func TestExtendBroadcastClearsReminder(t *testing.T) {
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	sent := now.AddDate(0, 0, -29)
+	repo := &stubRepo{saved: []BroadcastRecord{
+		{ID: "b1", Job: JobPosting{Title: "Go Dev", Contact: "@acme_hr"}, Channel: "@jobs", Status: StatusSent, CreatedAt: sent, LastSentAt: &sent},
+	}}
+	notifier := &reminderNotifier{}
+	policy := DefaultExpiryPolicy()
+	policy.Reminders = notifier
+	svc := NewService(&mockSender{}, repo, SimpleSummarizer{}, "@jobs").WithExpiryPolicy(policy)
+	svc.clock = func() time.Time { return now }
+
+	if _, err := svc.CheckExpiry(context.Background()); err != nil || len(notifier.seekers) != 1 {
+		t.Fatalf("expected a reminder to the contact, got %v (%v)", notifier.seekers, err)
+	}
+
+	record, err := svc.ExtendBroadcast(context.Background(), "b1", time.Time{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if record.ReminderSentAt != nil || !record.Job.ExpiresAt.Equal(now.AddDate(0, 0, 30)) {
+		t.Fatalf("expected expiry extended and reminder cleared, got %+v", record)
+	}
+	if changed, _ := svc.CheckExpiry(context.Background()); len(changed) != 0 {
+		t.Fatalf("expected extended vacancy left open, got %d changes", len(changed))
+	}
+}
+
+func TestSeniorityOf(t *testing.T) {
+	for posting, want := range map[JobPosting]string{
+		{Title: "Senior Go Team Lead"}:                     SeniorityLead,
+		{Title: "Go Developer", Experience: "Senior"}:      SenioritySenior,
+		{Title: "Internal Tools Engineer"}:                 SeniorityOther,
+		{Title: "Go Intern"}:                               SeniorityIntern,
+		{Title: "Go Developer", Experience: "2-3 years"}:   SeniorityMiddle,
+		{Title: "Backend Developer", Experience: "5+ yil"}: SenioritySenior,
+	} {
+		if got := SeniorityOf(posting); got != want {
+			t.Fatalf("%+v: expected %s, got %s", posting, want, got)
+		}
+	}
+}
+
+func TestPostDigestGroupsPublishedVacancies(t *testing.T) {
+	now := time.Date(2024, 5, 13, 9, 0, 0, 0, time.UTC)
+	at := func(days int) *time.Time {
+		t := now.AddDate(0, 0, -days)
+		return &t
+	}
+	senior := JobPosting{Title: "Senior Go Dev", Company: "Acme", Location: "Tashkent", Salary: "$4k"}
+	repo := &stubRepo{saved: []BroadcastRecord{
+		{ID: "1", Job: senior, Fingerprint: Fingerprint(senior), Channel: "-1001234", Status: StatusSent, MessageIDs: []int{11}, CreatedAt: *at(2), LastSentAt: at(2)},
+		{ID: "2", Job: JobPosting{Title: "Junior Go Dev", Company: "Beta", Location: "Remote"}, Channel: "-1001234", Status: StatusEdited, MessageIDs: []int{12}, CreatedAt: *at(3), LastSentAt: at(3)},
+		{ID: "3", Job: JobPosting{Title: "Senior SRE", Company: "Gamma", Location: "Remote"}, Channel: "-1001234", Status: StatusRetracted, MessageIDs: []int{13}, CreatedAt: *at(1), LastSentAt: at(1)},
+		{ID: "4", Job: JobPosting{Title: "Senior QA", Company: "Delta"}, Channel: "-1001234", Status: StatusFailed, CreatedAt: *at(1), LastSentAt: at(1)},
+		{ID: "5", Job: JobPosting{Title: "Senior PM", Company: "Old"}, Channel: "-1001234", Status: StatusSent, MessageIDs: []int{5}, CreatedAt: *at(9), LastSentAt: at(9)},
+		// The same vacancy fanned out to another channel.
+		{ID: "6", Job: senior, Fingerprint: Fingerprint(senior), Channel: "@remote", Status: StatusSent, MessageIDs: []int{16}, CreatedAt: *at(2), LastSentAt: at(2)},
+		{ID: "7", Job: JobPosting{Title: "Remote Rust Dev", Company: "Eta"}, Channel: "@remote", Status: StatusSent, MessageIDs: []int{17}, CreatedAt: *at(2), LastSentAt: at(2)},
+		// Queued for review before the window and approved inside it.
+		{ID: "8", Job: JobPosting{Title: "Junior Go Tester", Company: "Theta"}, Channel: "-1001234", Status: StatusSent, MessageIDs: []int{18}, CreatedAt: *at(12), LastSentAt: at(1)},
+	}}
+	sender := &channelSender{}
+	svc := NewService(sender, repo, SimpleSummarizer{}, "-1001234")
+	svc.clock = func() time.Time { return now }
+
+	text, err := svc.PostDigest(context.Background(), DigestOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sender.messages["-1001234"]) != 1 || sender.messages["-1001234"][0] != text {
+		t.Fatalf("expected digest sent to the channel, got %v", sender.messages)
+	}
+	juniorAt, seniorAt := strings.Index(text, "*Junior*"), strings.Index(text, "*Senior*")
+	if juniorAt < 0 || seniorAt < juniorAt {
+		t.Fatalf("expected sections ordered by seniority, got %q", text)
+	}
+	for _, want := range []string{
+		"[Senior Go Dev](https://t.me/c/1234/11) — Acme, $4k",
+		"[Junior Go Dev](https://t.me/c/1234/12) — Beta",
+		"[Junior Go Tester](https://t.me/c/1234/18) — Theta",
+		"_Tashkent_",
+		"3 vacancies in total",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("expected %q in digest %q", want, text)
+		}
+	}
+	for _, skipped := range []string{"SRE", "QA", "PM", "Rust"} {
+		if strings.Contains(text, skipped) {
+			t.Fatalf("expected %s skipped, got %q", skipped, text)
+		}
+	}
+	all, err := svc.BuildDigest(context.Background(), "", now.AddDate(0, 0, -7), now)
+	if err != nil || all.Count != 4 {
+		t.Fatalf("expected the fanned-out vacancy listed once across channels, got %d (%v)", all.Count, err)
+	}
+
+	svc.clock = func() time.Time { return now.AddDate(0, 1, 0) }
+	if _, err := svc.PostDigest(context.Background(), DigestOptions{}); !errors.Is(err, ErrEmptyDigest) {
+		t.Fatalf("expected ErrEmptyDigest, got %v", err)
+	}
+	if len(sender.messages["-1001234"]) != 1 {
+		t.Fatalf("expected empty digest not sent")
+	}
+}
+
+func TestBuiltinDigestTemplatesParse(t *testing.T) {
+	for _, lang := range CardLanguages {
+		if _, err := BuiltinDigestTemplate(lang); err != nil {
+			t.Fatalf("%s: unexpected error: %v", lang, err)
+		}
+	}
+	if _, err := ParseDigestTemplate("bad", "{{range .Sections}}{{.Title}}{{end}}"); err == nil {
+		t.Fatalf("expected invalid digest template rejected")
+	}
+}
+
+func TestDigestScheduleNext(t *testing.T) {
+	schedule := DigestSchedule{Weekday: time.Monday, Hour: 9, Location: time.UTC}
+	for from, want := range map[time.Time]time.Time{
+		time.Date(2024, 5, 8, 12, 0, 0, 0, time.UTC): time.Date(2024, 5, 13, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 5, 13, 8, 0, 0, 0, time.UTC): time.Date(2024, 5, 13, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, 5, 13, 9, 0, 0, 0, time.UTC): time.Date(2024, 5, 20, 9, 0, 0, 0, time.UTC),
+	} {
+		if got := schedule.Next(from); !got.Equal(want) {
+			t.Fatalf("Next(%s): expected %s, got %s", from, want, got)
+		}
+	}
+}
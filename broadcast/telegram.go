@@ -0,0 +1,286 @@
+package broadcast
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// TelegramMessageLimit is the maximum length of a Telegram text message.
+const TelegramMessageLimit = 4096
+
+// telegramAPI is the subset of tgbotapi.BotAPI used by TelegramSender.
+type telegramAPI interface {
+	Send(c tgbotapi.Chattable) (tgbotapi.Message, error)
+	Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error)
+}
+
+// TelegramSender posts broadcast cards to a Telegram channel as MarkdownV2.
+// Channels are addressed either by "@username" or by numeric chat ID.
+type TelegramSender struct {
+	api   telegramAPI
+	limit int
+}
+
+// NewTelegramSender constructs a sender using an authenticated bot.
+func NewTelegramSender(api *tgbotapi.BotAPI) *TelegramSender {
+	return &TelegramSender{api: api, limit: TelegramMessageLimit}
+}
+
+// Send implements Sender.
+func (s *TelegramSender) Send(ctx context.Context, channel, message string) error {
+	_, err := s.SendMessages(ctx, channel, message)
+	return err
+}
+
+// SendMessages posts the message, splitting it into several posts when it is
+// over the Telegram length limit, and returns the IDs of the posted messages.
+// If a later part fails, the parts already posted are deleted so a retry does
+// not leave duplicates in the channel.
+func (s *TelegramSender) SendMessages(ctx context.Context, channel, message string) ([]int, error) {
+	chat, err := parseChat(channel)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int
+	for _, part := range SplitMessage(message, s.limit) {
+		if err := ctx.Err(); err != nil {
+			s.deleteAll(chat, ids)
+			return nil, err
+		}
+
+		msg := tgbotapi.MessageConfig{
+			BaseChat:              tgbotapi.BaseChat{ChatID: chat.id, ChannelUsername: chat.username},
+			Text:                  part,
+			ParseMode:             tgbotapi.ModeMarkdownV2,
+			DisableWebPagePreview: true,
+		}
+		sent, err := s.api.Send(msg)
+		if err != nil {
+			s.deleteAll(chat, ids)
+			return nil, wrapTelegramError(err)
+		}
+		ids = append(ids, sent.MessageID)
+	}
+	return ids, nil
+}
+
+func (s *TelegramSender) deleteAll(chat telegramChat, ids []int) {
+	for _, id := range ids {
+		_, _ = s.api.Request(tgbotapi.DeleteMessageConfig{ChatID: chat.id, ChannelUsername: chat.username, MessageID: id})
+	}
+}
+
+type telegramChat struct {
+	id       int64
+	username string
+}
+
+func parseChat(channel string) (telegramChat, error) {
+	channel = strings.TrimSpace(channel)
+	if strings.HasPrefix(channel, "@") {
+		return telegramChat{username: channel}, nil
+	}
+	id, err := strconv.ParseInt(channel, 10, 64)
+	if err != nil {
+		return telegramChat{}, &PermanentError{Err: fmt.Errorf("invalid telegram channel %q: want @username or chat ID", channel)}
+	}
+	return telegramChat{id: id}, nil
+}
+
+// wrapTelegramError converts Bot API errors into RateLimitError or
+// PermanentError so the retry policy can classify them.
+func wrapTelegramError(err error) error {
+	var apiErr *tgbotapi.Error
+	if !errors.As(err, &apiErr) {
+		return err
+	}
+
+	switch {
+	case apiErr.Code == 429:
+		return &RateLimitError{RetryAfter: time.Duration(apiErr.RetryAfter) * time.Second, Err: err}
+	case apiErr.Code == 400 || apiErr.Code == 401 || apiErr.Code == 403:
+		return &PermanentError{Err: err}
+	default:
+		return err
+	}
+}
+
+// SplitMessage breaks text into parts of at most limit UTF-16 code units, the
+// unit Telegram uses for its length limit. Parts are split on line breaks
+// where possible and never inside a MarkdownV2 escape sequence.
+func SplitMessage(text string, limit int) []string {
+	if limit <= 0 {
+		limit = TelegramMessageLimit
+	}
+	if textLength(text) <= limit {
+		return []string{text}
+	}
+
+	var (
+		parts   []string
+		current strings.Builder
+		size    int
+	)
+	flush := func() {
+		if part := strings.TrimSpace(current.String()); part != "" {
+			parts = append(parts, part)
+		}
+		current.Reset()
+		size = 0
+	}
+
+	for _, line := range strings.SplitAfter(text, "\n") {
+		lineSize := textLength(line)
+		if size+lineSize > limit {
+			flush()
+		}
+		for lineSize > limit {
+			head, rest := cutLine(line, limit)
+			parts = append(parts, strings.TrimSpace(head))
+			line, lineSize = rest, textLength(rest)
+		}
+		current.WriteString(line)
+		size += lineSize
+	}
+	flush()
+
+	return parts
+}
+
+// cutLine splits line so that head fits in limit, preferring the last space
+// and backing off rather than separating a backslash from the character it
+// escapes.
+func cutLine(line string, limit int) (string, string) {
+	runes := []rune(line)
+	end, size := 0, 0
+	for end < len(runes) {
+		n := runeLength(runes[end])
+		if size+n > limit {
+			break
+		}
+		size += n
+		end++
+	}
+
+	if end < len(runes) {
+		for i := end - 1; i > end/2; i-- {
+			if runes[i] == ' ' {
+				end = i
+				break
+			}
+		}
+	}
+
+	backslashes := 0
+	for i := end - 1; i >= 0 && runes[i] == '\\'; i-- {
+		backslashes++
+	}
+	if backslashes%2 == 1 && end > 1 {
+		end--
+	}
+	if end == 0 {
+		end = 1
+	}
+
+	return string(runes[:end]), string(runes[end:])
+}
+
+func textLength(s string) int {
+	n := 0
+	for _, r := range s {
+		n += runeLength(r)
+	}
+	return n
+}
+
+// runeLength reports how many UTF-16 code units encode r.
+func runeLength(r rune) int {
+	if utf16.IsSurrogate(r) || r < 0x10000 {
+		return 1
+	}
+	return 2
+}
+
+// EditMessages replaces the text of previously posted messages. When the new
+// text needs fewer parts the extra messages are deleted; when it needs more,
+// the additional parts are posted as new messages. It returns the IDs that
+// now make up the post.
+func (s *TelegramSender) EditMessages(ctx context.Context, channel string, messageIDs []int, message string) ([]int, error) {
+	chat, err := parseChat(channel)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := SplitMessage(message, s.limit)
+	ids := make([]int, 0, len(parts))
+	for i, part := range parts {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		if i >= len(messageIDs) {
+			sent, err := s.api.Send(tgbotapi.MessageConfig{
+				BaseChat:              tgbotapi.BaseChat{ChatID: chat.id, ChannelUsername: chat.username},
+				Text:                  part,
+				ParseMode:             tgbotapi.ModeMarkdownV2,
+				DisableWebPagePreview: true,
+			})
+			if err != nil {
+				return nil, wrapTelegramError(err)
+			}
+			ids = append(ids, sent.MessageID)
+			continue
+		}
+
+		edit := tgbotapi.EditMessageTextConfig{
+			BaseEdit:              tgbotapi.BaseEdit{ChatID: chat.id, ChannelUsername: chat.username, MessageID: messageIDs[i]},
+			Text:                  part,
+			ParseMode:             tgbotapi.ModeMarkdownV2,
+			DisableWebPagePreview: true,
+		}
+		if _, err := s.api.Request(edit); err != nil && !isNotModified(err) {
+			return nil, wrapTelegramError(err)
+		}
+		ids = append(ids, messageIDs[i])
+	}
+
+	if len(messageIDs) > len(parts) {
+		if err := s.DeleteMessages(ctx, channel, messageIDs[len(parts):]); err != nil {
+			return nil, err
+		}
+	}
+	return ids, nil
+}
+
+// DeleteMessages removes previously posted messages from the channel. Telegram
+// only lets bots delete channel posts younger than 48 hours; older posts should
+// be edited instead.
+func (s *TelegramSender) DeleteMessages(ctx context.Context, channel string, messageIDs []int) error {
+	chat, err := parseChat(channel)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range messageIDs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, err := s.api.Request(tgbotapi.DeleteMessageConfig{ChatID: chat.id, ChannelUsername: chat.username, MessageID: id}); err != nil {
+			return wrapTelegramError(err)
+		}
+	}
+	return nil
+}
+
+// isNotModified reports Telegram's response to an edit that keeps the same text.
+func isNotModified(err error) bool {
+	return strings.Contains(err.Error(), "message is not modified")
+}
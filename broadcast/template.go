@@ -0,0 +1,192 @@
+package broadcast
+
+import (
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var builtinTemplates embed.FS
+
+// CardLanguages lists the languages of the built-in card templates.
+var CardLanguages = []string{"en", "ru", "uz"}
+
+// CardData is the value card templates are executed with. Every field is
+// already escaped for Telegram MarkdownV2, so templates only add the
+// formatting around them.
+type CardData struct {
+	Title       string
+	Company     string
+	Location    string
+	Salary      string
+	Experience  string
+	Description string
+	Contact     string
+	Summary     string
+	// ApplyURL is escaped for use as a MarkdownV2 link target, e.g.
+	// [Apply]({{.ApplyURL}}).
+	ApplyURL string
+}
+
+// CardTemplate renders broadcast cards from a text/template.
+type CardTemplate struct {
+	name string
+	tmpl *template.Template
+}
+
+// defaultCard is the English built-in template used by FormatCard.
+var defaultCard = mustBuiltinCardTemplate("en")
+
+// ParseCardTemplate parses and validates a card template. The template is
+// rendered against a complete and an empty sample posting; it must produce
+// text for the complete one and well-formed MarkdownV2 for both, so mistakes
+// surface at startup instead of as Telegram errors.
+func ParseCardTemplate(name, text string) (*CardTemplate, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse card template %s: %w", name, err)
+	}
+	card := &CardTemplate{name: name, tmpl: tmpl}
+
+	samples := []JobPosting{
+		{
+			Title:       "Senior Go Developer (Payments)",
+			Company:     "Example Inc.",
+			Location:    "Tashkent / Remote",
+			Salary:      "$3,000-$4,500",
+			Experience:  "3+ years",
+			Description: "Build APIs with Go, Postgres & Kafka.",
+			Contact:     "@example_hr",
+			ApplyURL:    "https://example.com/apply?id=(42)",
+		},
+		{},
+	}
+	for i, sample := range samples {
+		out, err := card.Render(sample, "Payments team is hiring!")
+		if err != nil {
+			return nil, fmt.Errorf("validate card template %s: %w", name, err)
+		}
+		if i == 0 && out == "" {
+			return nil, fmt.Errorf("validate card template %s: renders an empty card", name)
+		}
+		if err := checkMarkdownV2(out); err != nil {
+			return nil, fmt.Errorf("validate card template %s: %w", name, err)
+		}
+	}
+	return card, nil
+}
+
+// LoadCardTemplate reads and validates a card template file. The template is
+// named after the file without its extension.
+func LoadCardTemplate(path string) (*CardTemplate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read card template: %w", err)
+	}
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return ParseCardTemplate(name, string(data))
+}
+
+// BuiltinCardTemplate returns the built-in template for one of CardLanguages.
+func BuiltinCardTemplate(lang string) (*CardTemplate, error) {
+	data, err := builtinTemplates.ReadFile("templates/card_" + lang + ".tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("no built-in card template for language %q", lang)
+	}
+	return ParseCardTemplate("card_"+lang, string(data))
+}
+
+func mustBuiltinCardTemplate(lang string) *CardTemplate {
+	card, err := BuiltinCardTemplate(lang)
+	if err != nil {
+		panic(err)
+	}
+	return card
+}
+
+// Name returns the template name.
+func (c *CardTemplate) Name() string {
+	return c.name
+}
+
+// Render executes the template for a posting and its summary.
+func (c *CardTemplate) Render(posting JobPosting, summary string) (string, error) {
+	var b strings.Builder
+	err := c.tmpl.Execute(&b, CardData{
+		Title:       EscapeMarkdownV2(posting.Title),
+		Company:     EscapeMarkdownV2(posting.Company),
+		Location:    EscapeMarkdownV2(posting.Location),
+		Salary:      EscapeMarkdownV2(posting.Salary),
+		Experience:  EscapeMarkdownV2(posting.Experience),
+		Description: EscapeMarkdownV2(posting.Description),
+		Contact:     EscapeMarkdownV2(posting.Contact),
+		Summary:     EscapeMarkdownV2(summary),
+		ApplyURL:    linkURLReplacer.Replace(posting.ApplyURL),
+	})
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// Format implements CardFormatter. Should the template fail for a posting,
+// the card is rendered with the default template instead.
+func (c *CardTemplate) Format(posting JobPosting, summary string) string {
+	card, err := c.Render(posting, summary)
+	if err != nil && c != defaultCard {
+		return FormatCard(posting, summary)
+	}
+	return card
+}
+
+// linkURLReplacer escapes the characters MarkdownV2 reserves inside link URLs.
+var linkURLReplacer = strings.NewReplacer("\\", "\\\\", ")", "\\)")
+
+// checkMarkdownV2 catches the common MarkdownV2 mistakes in template text:
+// reserved characters left unescaped and unbalanced entity markers. Code spans
+// and link URLs are skipped since different escaping rules apply there.
+func checkMarkdownV2(text string) error {
+	const (
+		markers  = "*_~|"
+		reserved = "#+-=.!{}"
+	)
+	counts := make(map[rune]int)
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\\':
+			i++
+		case r == '`':
+			for i++; i < len(runes) && runes[i] != '`'; i++ {
+				if runes[i] == '\\' {
+					i++
+				}
+			}
+			if i >= len(runes) {
+				return fmt.Errorf("unclosed code span in MarkdownV2 text")
+			}
+		case r == ']' && i+1 < len(runes) && runes[i+1] == '(':
+			for i < len(runes) && runes[i] != ')' {
+				if runes[i] == '\\' {
+					i++
+				}
+				i++
+			}
+		case strings.ContainsRune(markers, r):
+			counts[r]++
+		case strings.ContainsRune(reserved, r):
+			return fmt.Errorf("unescaped %q in MarkdownV2 text", r)
+		}
+	}
+	for _, r := range markers {
+		if counts[r]%2 != 0 {
+			return fmt.Errorf("unbalanced %q in MarkdownV2 text", r)
+		}
+	}
+	return nil
+}
@@ -2,12 +2,25 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 	"time"
 
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
 	"github.com/Golangjobsuz/golangjobsuz/broadcast"
 	"github.com/Golangjobsuz/golangjobsuz/contact"
+	"github.com/Golangjobsuz/golangjobsuz/internal/extraction"
+	"github.com/Golangjobsuz/golangjobsuz/internal/notifier"
+	"github.com/Golangjobsuz/golangjobsuz/internal/platform/database"
+	"github.com/Golangjobsuz/golangjobsuz/internal/platform/metrics"
 )
 
 // consoleSender writes messages to stdout for demonstration purposes.
@@ -31,12 +44,171 @@ func (consoleNotifier) NotifyAdmin(_ context.Context, message string) error {
 	return nil
 }
 
+func (consoleNotifier) AskSeeker(_ context.Context, seekerContact, message, requestID string) error {
+	fmt.Printf("[notify seeker %s]\n%s\n[Accept: %s] [Decline: %s]\n[Report spam: %s] [Report abuse: %s]\n", seekerContact, message,
+		contact.ConsentData(contact.ActionAccept, requestID), contact.ConsentData(contact.ActionDecline, requestID),
+		contact.ReportData(contact.ReportSpam, requestID), contact.ReportData(contact.ReportAbuse, requestID))
+	return nil
+}
+
+// newSummarizer uses the configured AI provider for summaries when an API key
+// is present and the deterministic summarizer otherwise.
+func newSummarizer(ctx context.Context, observer extraction.Observer) broadcast.Summarizer {
+	apiKey, provider, model := os.Getenv("AI_API_KEY"), os.Getenv("AI_PROVIDER"), os.Getenv("AI_MODEL")
+	if apiKey == "" {
+		return broadcast.SimpleSummarizer{}
+	}
+
+	switch provider {
+	case "openai":
+		client := extraction.NewOpenAIClient(apiKey, model, 200, 1, nil, nil, extraction.DefaultOpenAICosts).WithObserver(observer)
+		return broadcast.NewLLMSummarizer(client, provider, 280, 10*time.Second, nil)
+	case "gemini":
+		client, err := extraction.NewGeminiClient(ctx, apiKey, model, 200, 1, nil, nil, extraction.DefaultGeminiCost)
+		if err != nil {
+			log.Printf("gemini summarizer unavailable: %v", err)
+			return broadcast.SimpleSummarizer{}
+		}
+		client.WithObserver(observer)
+		return broadcast.NewLLMSummarizer(client, provider, 280, 10*time.Second, nil)
+	default:
+		return broadcast.SimpleSummarizer{}
+	}
+}
+
+// newExtractor chains the AI_PROVIDER profile extractor with the
+// AI_FALLBACK_PROVIDER one, or returns nil when no API key is set. The
+// fallback uses AI_FALLBACK_API_KEY, or AI_API_KEY when that is empty. The
+// chain is guarded by the AI_*_BUDGET_USD limits, and the app stops when a
+// limit is set for a model without a price.
+func newExtractor(ctx context.Context, observer extraction.Observer, limits extraction.BudgetLimits) *extraction.BudgetGuard {
+	apiKey := os.Getenv("AI_API_KEY")
+	if apiKey == "" {
+		return nil
+	}
+	fallbackKey := os.Getenv("AI_FALLBACK_API_KEY")
+	if fallbackKey == "" {
+		fallbackKey = apiKey
+	}
+
+	var providers []extraction.Provider
+	for _, cfg := range []struct{ name, model, key string }{
+		{os.Getenv("AI_PROVIDER"), os.Getenv("AI_MODEL"), apiKey},
+		{os.Getenv("AI_FALLBACK_PROVIDER"), os.Getenv("AI_FALLBACK_MODEL"), fallbackKey},
+	} {
+		if cfg.name == "" {
+			continue
+		}
+		if limits != (extraction.BudgetLimits{}) && !extraction.HasPrice(cfg.name, cfg.model) {
+			log.Fatalf("AI budget set, but %s model %q has no price in the extraction cost tables, so its spend would not be counted", cfg.name, cfg.model)
+		}
+		provider, err := extraction.NewProvider(ctx, cfg.name, cfg.key, cfg.model, nil, observer)
+		if err != nil {
+			log.Printf("extraction provider %s unavailable: %v", cfg.name, err)
+			continue
+		}
+		provider.Timeout = 30 * time.Second
+		providers = append(providers, provider)
+	}
+	if len(providers) == 0 {
+		return nil
+	}
+	chain := extraction.NewFallbackClient(nil, providers...)
+	return extraction.NewBudgetGuard(chain, limits, notifier.New(slog.Default()))
+}
+
+// budgetLimits reads the AI spend limits in USD; unset limits are zero.
+func budgetLimits() extraction.BudgetLimits {
+	limit := func(key string) float64 {
+		value, _ := strconv.ParseFloat(os.Getenv(key), 64)
+		return value
+	}
+	return extraction.BudgetLimits{
+		Daily:       limit("AI_DAILY_BUDGET_USD"),
+		Monthly:     limit("AI_MONTHLY_BUDGET_USD"),
+		UserDaily:   limit("AI_USER_DAILY_BUDGET_USD"),
+		UserMonthly: limit("AI_USER_MONTHLY_BUDGET_USD"),
+	}
+}
+
+// cardFormat returns the card formatter configured by <prefix>CARD_TEMPLATE (a
+// template file) or <prefix>CARD_LANG (a built-in template), or nil for the
+// default card. Invalid templates stop the app at startup.
+func cardFormat(prefix string) broadcast.CardFormatter {
+	var (
+		card *broadcast.CardTemplate
+		err  error
+	)
+	if path := os.Getenv(prefix + "CARD_TEMPLATE"); path != "" {
+		card, err = broadcast.LoadCardTemplate(path)
+	} else if lang := os.Getenv(prefix + "CARD_LANG"); lang != "" {
+		card, err = broadcast.BuiltinCardTemplate(lang)
+	} else {
+		return nil
+	}
+	if err != nil {
+		log.Fatalf("card template: %v", err)
+	}
+	return card.Format
+}
+
+// routes posts every vacancy to the main channel and, when configured, also to
+// the remote, Tashkent onsite and internship channels, each with its own card
+// template.
+func routes(mainChannel string) []broadcast.Route {
+	routes := []broadcast.Route{{Channel: mainChannel, Format: cardFormat("")}}
+	if channel := os.Getenv("REMOTE_CHANNEL_ID"); channel != "" {
+		routes = append(routes, broadcast.Route{Channel: channel, Match: broadcast.LocationMatches("remote", "masofaviy", "удален"), Format: cardFormat("REMOTE_")})
+	}
+	if channel := os.Getenv("TASHKENT_CHANNEL_ID"); channel != "" {
+		routes = append(routes, broadcast.Route{Channel: channel, Format: cardFormat("TASHKENT_"), Match: broadcast.AllOf(
+			broadcast.LocationMatches("tashkent", "toshkent", "ташкент"),
+			broadcast.Not(broadcast.LocationMatches("remote")),
+		)})
+	}
+	if channel := os.Getenv("INTERNS_CHANNEL_ID"); channel != "" {
+		routes = append(routes, broadcast.Route{Channel: channel, Format: cardFormat("INTERNS_"), Match: broadcast.AnyOf(
+			broadcast.TitleMatches("intern", "стажер", "amaliyotchi"),
+			broadcast.ExperienceMatches("no experience", "без опыта", "tajribasiz"),
+		)})
+	}
+	return routes
+}
+
 func main() {
-	ctx := context.Background()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// AI usage metrics, served on METRICS_ADDRESS when it is set.
+	metricsRegistry := metrics.New()
+	aiMetrics := metrics.NewExtractionMetrics(metricsRegistry)
+	metricsAddr := os.Getenv("METRICS_ADDRESS")
+	if metricsAddr != "" {
+		go func() {
+			srv := &http.Server{Addr: metricsAddr, Handler: metricsRegistry.Handler()}
+			if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Printf("metrics server failed: %v", err)
+			}
+		}()
+	}
+
+	// Broadcast example: posts to Telegram when BOT_TOKEN and CHANNEL_ID are
+	// set, otherwise prints the card to stdout.
+	var sender broadcast.Sender = consoleSender{}
+	channel := "#vacancies"
+	if token, channelID := os.Getenv("BOT_TOKEN"), os.Getenv("CHANNEL_ID"); token != "" && channelID != "" {
+		api, err := tgbotapi.NewBotAPI(token)
+		if err != nil {
+			log.Fatalf("telegram bot: %v", err)
+		}
+		sender = broadcast.NewTelegramSender(api)
+		channel = channelID
+	}
 
-	// Broadcast example
 	repo := broadcast.NewFileRepo("data/broadcasts.json")
-	svc := broadcast.NewService(consoleSender{}, repo, broadcast.SimpleSummarizer{}, "#vacancies")
+	svc := broadcast.NewService(sender, repo, newSummarizer(ctx, aiMetrics), channel).
+		WithRoutes(routes(channel)...).
+		WithApplyTracking(os.Getenv("TRACKING_BASE_URL"))
 	posting := broadcast.JobPosting{
 		Title:       "Go Backend Engineer",
 		Company:     "ExampleCo",
@@ -47,24 +219,78 @@ func main() {
 		Contact:     "talent@example.com",
 	}
 
-	if _, err := svc.PostBroadcast(ctx, posting, broadcast.Options{}); err != nil {
-		log.Fatalf("broadcast failed: %v", err)
+	records, err := svc.FanOut(ctx, posting, broadcast.Options{DryRun: os.Getenv("DRY_RUN") != ""})
+	if err != nil {
+		if !errors.Is(err, broadcast.ErrDuplicate) {
+			log.Fatalf("broadcast failed: %v", err)
+		}
+		fmt.Printf("skipped broadcast: %v\n\n", err)
+	}
+	for _, record := range records {
+		if record.DryRun {
+			fmt.Printf("[preview %s]\n%s\n\n", record.Channel, record.Preview)
+		}
 	}
 
 	// Contact request example
 	contactRepo := contact.NewMemoryLogRepo()
 	contactSvc := contact.NewService(consoleNotifier{}, contactRepo)
-	if _, err := contactSvc.HandleRequest(ctx, contact.Request{
+	request := contact.Request{
 		RecruiterName:    "Rita Recruiter",
 		RecruiterCompany: "Talent Partners",
 		RecruiterContact: "rita@example.com",
 		Role:             posting.Title,
+		Salary:           posting.Salary,
 		SeekerName:       "Sam Seeker",
 		SeekerContact:    "@samseeker",
+		SeekerLanguage:   os.Getenv("CONTACT_LANG"),
 		Notes:            "Available for a quick intro call",
-	}); err != nil {
+	}
+	preview, err := contactSvc.Preview(ctx, request)
+	if err != nil {
+		log.Fatalf("contact preview failed: %v", err)
+	}
+	fmt.Printf("[contact preview]\n%s\n\n", preview)
+	entry, err := contactSvc.HandleRequest(ctx, request)
+	if err != nil {
 		log.Fatalf("contact flow failed: %v", err)
 	}
+	// The seeker presses Accept; only now are their details shared.
+	if _, err := contactSvc.Accept(ctx, entry.ID); err != nil {
+		log.Fatalf("contact consent failed: %v", err)
+	}
+
+	// Profile extraction example, only with a configured AI provider.
+	if guard := newExtractor(ctx, aiMetrics, budgetLimits()); guard != nil {
+		// With DATABASE_DSN the spend is kept in ai_spend across runs.
+		pool, err := database.Connect(ctx, os.Getenv("DATABASE_DSN"))
+		if err != nil {
+			log.Fatalf("connect database: %v", err)
+		}
+		if pool != nil {
+			defer pool.Close()
+			guard.WithStore(extraction.NewPostgresSpendStore(pool))
+			if err := guard.LoadSpend(ctx); err != nil {
+				log.Fatalf("load AI spend: %v", err)
+			}
+		}
+		metrics.RegisterBudgetSpend(metricsRegistry, guard)
+		draft, err := guard.Extract(extraction.WithUser(ctx, "samseeker"), "Sam Seeker, Tashkent. Go developer with 4 years of Postgres, gRPC and Kubernetes. sam@example.com")
+		if err != nil {
+			log.Printf("profile extraction failed: %v", err)
+		} else {
+			fmt.Printf("[extracted by %s/%s] %s: %v\n", draft.Provider, draft.Model, draft.Profile.Name, draft.Profile.Skills)
+			for _, skip := range draft.Skipped {
+				fmt.Printf("  skipped %s (%s): %s\n", skip.Provider, skip.Reason, skip.Error)
+			}
+		}
+		daily, monthly := guard.Spent("")
+		fmt.Printf("[ai spend] today $%.6f, this month $%.6f\n", daily, monthly)
+	}
 
 	time.Sleep(50 * time.Millisecond)
+	if metricsAddr != "" {
+		log.Printf("serving metrics on %s until interrupted", metricsAddr)
+		<-ctx.Done()
+	}
 }
@@ -3,19 +3,25 @@ package main
 import (
 	"context"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"github.com/Golangjobsuz/golangjobsuz/broadcast"
+	"github.com/Golangjobsuz/golangjobsuz/contact"
 	"github.com/Golangjobsuz/golangjobsuz/internal/ai"
+	"github.com/Golangjobsuz/golangjobsuz/internal/audit"
+	"github.com/Golangjobsuz/golangjobsuz/internal/notifier"
 	"github.com/Golangjobsuz/golangjobsuz/internal/platform/config"
 	"github.com/Golangjobsuz/golangjobsuz/internal/platform/database"
 	"github.com/Golangjobsuz/golangjobsuz/internal/platform/httpclient"
 	"github.com/Golangjobsuz/golangjobsuz/internal/platform/logger"
 	"github.com/Golangjobsuz/golangjobsuz/internal/platform/metrics"
 	"github.com/Golangjobsuz/golangjobsuz/internal/repo"
+	"github.com/Golangjobsuz/golangjobsuz/internal/store"
 	"github.com/Golangjobsuz/golangjobsuz/internal/telegram"
 	"github.com/Golangjobsuz/golangjobsuz/internal/usecase"
 )
@@ -67,6 +73,67 @@ func main() {
 		logg.Fatal().Err(err).Msg("initialize telegram bot")
 	}
 
+	// Vacancy moderation: broadcasts wait in the review queue until an admin
+	// approves them with /approve; approved ones are sent by the dispatcher.
+	// Published vacancies expire unless their contact answers /still_hiring.
+	// Recruiter contact requests reach seekers through the bot, or the admin
+	// relay when a seeker has not started it. Recruiters reported too often
+	// are suspended in the recruiter store.
+	if dbPool != nil {
+		adminIDs, err := telegram.ParseAdminIDs(os.Getenv("ADMIN_IDS"))
+		if err != nil {
+			logg.Fatal().Err(err).Msg("parse ADMIN_IDS")
+		}
+		moderationLog := audit.NewPostgresLog(dbPool)
+		chats, err := telegram.LoadChatBook(ctx, telegram.NewPostgresChatStore(dbPool))
+		if err != nil {
+			logg.Fatal().Err(err).Msg("load telegram chats")
+		}
+		contactNotifier := telegram.NewContactNotifier(bot.API(), chats, adminIDs)
+		expiry := broadcast.DefaultExpiryPolicy()
+		expiry.Reminders = contactNotifier
+		broadcasts := broadcast.NewService(
+			broadcast.NewTelegramSender(bot.API()),
+			broadcast.NewPostgresRepo(dbPool),
+			broadcast.SimpleSummarizer{},
+			os.Getenv("CHANNEL_ID"),
+		).WithModeration(moderationLog).
+			WithAnalytics(broadcast.NewPostgresAnalytics(dbPool), metrics.NewBroadcastMetrics(metricsRegistry)).
+			WithApplyTracking(os.Getenv("TRACKING_BASE_URL")).
+			WithExpiryPolicy(expiry)
+		bot.WithModeration(telegram.NewModeration(broadcasts, adminIDs)).
+			WithExpiryReplies(telegram.NewExpiryReplies(broadcasts, adminIDs, true))
+
+		recruiters, err := store.Load("data/store.json")
+		if err != nil {
+			logg.Fatal().Err(err).Msg("load recruiter store")
+		}
+		contacts := contact.NewService(
+			contactNotifier,
+			contact.NewPostgresLogRepo(dbPool),
+		).WithQuota(contact.DefaultQuotaPolicy(), contact.StoreRecruiters{Store: recruiters}, moderationLog).
+			WithConsent(contact.StoreDirectory{Store: recruiters}, contact.NewPostgresAccess(dbPool), 0).
+			WithRelayInbox(contact.NewPostgresThreadRepo(dbPool), moderationLog).
+			WithAbuseReports(contact.DefaultReportThreshold, contact.StoreRecruiters{Store: recruiters}, notifier.New(slog.Default()), moderationLog)
+		bot.WithChatBook(chats).
+			WithContactConsent(telegram.NewContactConsent(contacts)).
+			WithRelayInbox(telegram.NewRelayInbox(contacts, adminIDs))
+
+		go broadcast.NewDispatcher(broadcasts, time.Minute, nil).Run(ctx)
+		go broadcast.NewViewCollector(broadcasts, time.Hour, 0, nil).Run(ctx)
+		go broadcast.NewExpiryWatcher(broadcasts, time.Hour, nil).Run(ctx)
+
+		// Weekly digest of the past week's vacancies, Mondays 09:00 Tashkent time.
+		if lang := os.Getenv("DIGEST_LANG"); lang != "" {
+			digest, err := broadcast.BuiltinDigestTemplate(lang)
+			if err != nil {
+				logg.Fatal().Err(err).Msg("load digest template")
+			}
+			schedule := broadcast.DigestSchedule{Weekday: time.Monday, Hour: 9, Location: time.FixedZone("Asia/Tashkent", 5*60*60)}
+			go broadcast.NewDigestScheduler(broadcasts, schedule, broadcast.DigestOptions{Template: digest}, nil).Run(ctx)
+		}
+	}
+
 	if err := bot.Start(ctx); err != nil {
 		if err == context.Canceled {
 			logg.Info().Msg("bot context canceled; exiting")
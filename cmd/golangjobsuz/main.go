@@ -1,13 +1,23 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"sort"
 	"strings"
+	"time"
 
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/Golangjobsuz/golangjobsuz/broadcast"
+	"github.com/Golangjobsuz/golangjobsuz/contact"
+	"github.com/Golangjobsuz/golangjobsuz/internal/audit"
 	"github.com/Golangjobsuz/golangjobsuz/internal/commands"
+	"github.com/Golangjobsuz/golangjobsuz/internal/platform/database"
 	"github.com/Golangjobsuz/golangjobsuz/internal/search"
 	"github.com/Golangjobsuz/golangjobsuz/internal/store"
 )
@@ -31,6 +41,18 @@ func main() {
 		searchCommand(s, os.Args[2:])
 	case "profile":
 		profileCommand(s, os.Args[2:])
+	case "import-broadcasts":
+		importBroadcastsCommand(os.Args[2:])
+	case "review":
+		reviewCommand(os.Args[2:])
+	case "stats":
+		statsCommand(os.Args[2:])
+	case "digest":
+		digestCommand(os.Args[2:])
+	case "relay":
+		relayCommand(os.Args[2:])
+	case "reports":
+		reportsCommand(s, os.Args[2:])
 	default:
 		usage()
 	}
@@ -42,6 +64,12 @@ func usage() {
 	fmt.Println("  admin   --action approve|ban --user <id> [--notes <text>] [--admin <id>]")
 	fmt.Println("  search  --skills 'go,grpc' --location Tashkent --seniority mid --days 14 --page 1 --page-size 5")
 	fmt.Println("  profile --id <profileID>")
+	fmt.Println("  import-broadcasts --from data/broadcasts.json [--dsn <postgres dsn>]")
+	fmt.Println("  stats   --by vacancy|company [--limit 20] [--dsn <postgres dsn>]")
+	fmt.Println("  digest  [--days 7] [--lang en|ru|uz] [--template <file>] [--channel <id>] [--dry-run] [--dsn <postgres dsn>]")
+	fmt.Println("  relay   [--id <threadID>] [--dsn <postgres dsn>]")
+	fmt.Println("  reports [--status open|upheld|dismissed|all] [--uphold|--dismiss <requestID>] [--admin <id>] [--dsn <postgres dsn>]")
+	fmt.Println("  review  --action list|approve|reject|edit [--id <broadcastID>] --reviewer <id> [--reason <text>] [--title ... --salary ...]")
 }
 
 func adminCommand(s *store.Store, args []string) {
@@ -132,6 +160,352 @@ func profileCommand(s *store.Store, args []string) {
 	fmt.Println("CTA: Reply /request_contact", p.ID, "to ask the bot to share details with you")
 }
 
+func importBroadcastsCommand(args []string) {
+	fs := flag.NewFlagSet("import-broadcasts", flag.ExitOnError)
+	from := fs.String("from", "data/broadcasts.json", "JSON broadcast log to import")
+	dsn := fs.String("dsn", os.Getenv("DATABASE_DSN"), "postgres connection string")
+	fs.Parse(args)
+
+	if *dsn == "" {
+		fs.Usage()
+		return
+	}
+
+	ctx := context.Background()
+	pool, err := database.Connect(ctx, *dsn)
+	if err != nil {
+		log.Fatalf("connect database: %v", err)
+	}
+	defer pool.Close()
+
+	n, err := broadcast.Import(ctx, broadcast.NewFileRepo(*from), broadcast.NewPostgresRepo(pool))
+	if err != nil {
+		log.Fatalf("import broadcasts: %v", err)
+	}
+	fmt.Printf("Imported %d broadcast records from %s\n", n, *from)
+}
+
+func reviewCommand(args []string) {
+	fs := flag.NewFlagSet("review", flag.ExitOnError)
+	action := fs.String("action", "list", "list, approve, reject or edit")
+	id := fs.String("id", "", "broadcast record id")
+	reviewer := fs.String("reviewer", "", "reviewer user id written to the audit log")
+	reason := fs.String("reason", "", "rejection reason")
+	dsn := fs.String("dsn", os.Getenv("DATABASE_DSN"), "postgres connection string")
+	fields := make(map[string]*string)
+	for _, name := range []string{"title", "company", "location", "salary", "experience", "description", "contact"} {
+		fields[name] = fs.String(name, "", "new "+name+" (edit only)")
+	}
+	fs.Parse(args)
+
+	if *dsn == "" || (*action != "list" && (*id == "" || *reviewer == "")) {
+		fs.Usage()
+		return
+	}
+
+	ctx := context.Background()
+	pool, err := database.Connect(ctx, *dsn)
+	if err != nil {
+		log.Fatalf("connect database: %v", err)
+	}
+	defer pool.Close()
+
+	// Review decisions never send directly: approved broadcasts are queued
+	// for the bot's dispatcher, so no sender is needed here.
+	svc := broadcast.NewService(nil, broadcast.NewPostgresRepo(pool), broadcast.SimpleSummarizer{}, "").
+		WithModeration(audit.NewPostgresLog(pool))
+
+	var record broadcast.BroadcastRecord
+	switch strings.ToLower(*action) {
+	case "list":
+		pending, err := svc.PendingReview(ctx)
+		if err != nil {
+			log.Fatalf("list review queue: %v", err)
+		}
+		fmt.Printf("%d broadcast(s) waiting for review\n", len(pending))
+		for _, r := range pending {
+			fmt.Printf("- [%s] %s at %s -> %s (submitted %s)\n", r.ID, r.Job.Title, r.Job.Company, r.Channel, r.CreatedAt.Format("2006-01-02 15:04"))
+		}
+		return
+	case "approve":
+		record, err = svc.ApproveBroadcast(ctx, *id, *reviewer)
+	case "reject":
+		record, err = svc.RejectBroadcast(ctx, *id, *reviewer, *reason)
+	case "edit":
+		changes := make(map[string]string)
+		for name, value := range fields {
+			if *value != "" {
+				changes[name] = *value
+			}
+		}
+		record, err = editPending(ctx, svc, *id, *reviewer, changes)
+	default:
+		fmt.Printf("unknown action %s\n", *action)
+		return
+	}
+	if err != nil {
+		log.Fatalf("review %s: %v", *action, err)
+	}
+	fmt.Printf("Broadcast %s is now %s\n", record.ID, record.Status)
+}
+
+func statsCommand(args []string) {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	by := fs.String("by", "vacancy", "group by vacancy or company")
+	limit := fs.Int("limit", 20, "maximum rows to print")
+	dsn := fs.String("dsn", os.Getenv("DATABASE_DSN"), "postgres connection string")
+	fs.Parse(args)
+
+	if *dsn == "" {
+		fs.Usage()
+		return
+	}
+
+	ctx := context.Background()
+	pool, err := database.Connect(ctx, *dsn)
+	if err != nil {
+		log.Fatalf("connect database: %v", err)
+	}
+	defer pool.Close()
+
+	svc := broadcast.NewService(nil, broadcast.NewPostgresRepo(pool), broadcast.SimpleSummarizer{}, "").
+		WithAnalytics(broadcast.NewPostgresAnalytics(pool), nil)
+
+	switch strings.ToLower(*by) {
+	case "vacancy":
+		stats, err := svc.VacancyStats(ctx)
+		if err != nil {
+			log.Fatalf("vacancy stats: %v", err)
+		}
+		fmt.Printf("%-20s %-30s %-20s %8s %8s\n", "RECORD", "TITLE", "COMPANY", "CLICKS", "VIEWS")
+		for i, v := range stats {
+			if i == *limit {
+				break
+			}
+			fmt.Printf("%-20s %-30.30s %-20.20s %8d %8d\n", v.RecordID, v.Title, v.Company, v.Clicks, v.Views)
+		}
+	case "company":
+		stats, err := svc.CompanyStats(ctx)
+		if err != nil {
+			log.Fatalf("company stats: %v", err)
+		}
+		fmt.Printf("%-30s %10s %8s %8s\n", "COMPANY", "VACANCIES", "CLICKS", "VIEWS")
+		for i, c := range stats {
+			if i == *limit {
+				break
+			}
+			fmt.Printf("%-30.30s %10d %8d %8d\n", c.Company, c.Broadcasts, c.Clicks, c.Views)
+		}
+	default:
+		fmt.Printf("unknown grouping %s\n", *by)
+	}
+}
+
+func digestCommand(args []string) {
+	fs := flag.NewFlagSet("digest", flag.ExitOnError)
+	days := fs.Int("days", 7, "how many days back the digest reaches")
+	lang := fs.String("lang", "en", "built-in digest template language")
+	templatePath := fs.String("template", "", "digest template file (overrides --lang)")
+	channel := fs.String("channel", os.Getenv("CHANNEL_ID"), "channel to post the digest to")
+	dryRun := fs.Bool("dry-run", false, "print the digest instead of posting it")
+	dsn := fs.String("dsn", os.Getenv("DATABASE_DSN"), "postgres connection string")
+	fs.Parse(args)
+
+	if *dsn == "" || (!*dryRun && *channel == "") {
+		fs.Usage()
+		return
+	}
+
+	tmpl, err := broadcast.BuiltinDigestTemplate(*lang)
+	if *templatePath != "" {
+		tmpl, err = broadcast.LoadDigestTemplate(*templatePath)
+	}
+	if err != nil {
+		log.Fatalf("digest template: %v", err)
+	}
+
+	var sender broadcast.Sender
+	if !*dryRun {
+		api, err := tgbotapi.NewBotAPI(os.Getenv("BOT_TOKEN"))
+		if err != nil {
+			log.Fatalf("telegram bot: %v", err)
+		}
+		sender = broadcast.NewTelegramSender(api)
+	}
+
+	ctx := context.Background()
+	pool, err := database.Connect(ctx, *dsn)
+	if err != nil {
+		log.Fatalf("connect database: %v", err)
+	}
+	defer pool.Close()
+
+	svc := broadcast.NewService(sender, broadcast.NewPostgresRepo(pool), broadcast.SimpleSummarizer{}, *channel)
+	text, err := svc.PostDigest(ctx, broadcast.DigestOptions{
+		Period:   time.Duration(*days) * 24 * time.Hour,
+		Template: tmpl,
+		DryRun:   *dryRun,
+	})
+	if errors.Is(err, broadcast.ErrEmptyDigest) {
+		fmt.Println("No vacancies to include in the digest")
+		return
+	}
+	if err != nil {
+		log.Fatalf("post digest: %v", err)
+	}
+	if *dryRun {
+		fmt.Println(text)
+		return
+	}
+	fmt.Printf("Digest posted to %s\n", *channel)
+}
+
+func relayCommand(args []string) {
+	fs := flag.NewFlagSet("relay", flag.ExitOnError)
+	id := fs.String("id", "", "show the messages of one thread instead of listing open threads")
+	dsn := fs.String("dsn", os.Getenv("DATABASE_DSN"), "postgres connection string")
+	fs.Parse(args)
+
+	if *dsn == "" {
+		fs.Usage()
+		return
+	}
+
+	ctx := context.Background()
+	pool, err := database.Connect(ctx, *dsn)
+	if err != nil {
+		log.Fatalf("connect database: %v", err)
+	}
+	defer pool.Close()
+
+	// Listing never notifies anyone; forwarding is done from the bot with
+	// /relay_forward.
+	svc := contact.NewService(nil, contact.NewPostgresLogRepo(pool)).
+		WithRelayInbox(contact.NewPostgresThreadRepo(pool), nil)
+
+	if *id != "" {
+		thread, err := svc.GetThread(ctx, *id)
+		if err != nil {
+			log.Fatalf("load thread: %v", err)
+		}
+		fmt.Printf("Thread %s (%s): %s (%s) -> %s about %s\n", thread.ID, thread.Status,
+			thread.Request.RecruiterName, thread.Request.RecruiterCompany, thread.Request.SeekerName, thread.Request.Role)
+		for _, m := range thread.Messages {
+			fmt.Printf("- %s %-9s [%s] %s\n", m.CreatedAt.Format("2006-01-02 15:04"), m.From, m.Status, m.Text)
+			if m.Forwarded != "" && m.Forwarded != m.Text {
+				fmt.Printf("    forwarded as: %s\n", m.Forwarded)
+			}
+		}
+		return
+	}
+
+	threads, err := svc.OpenThreads(ctx)
+	if err != nil {
+		log.Fatalf("list relay threads: %v", err)
+	}
+	fmt.Printf("%d open relay thread(s)\n", len(threads))
+	for _, t := range threads {
+		pending := "-"
+		if i := t.Pending(); i >= 0 {
+			pending = "waiting from " + string(t.Messages[i].From)
+		}
+		if t.SeekerUnreachable != "" {
+			pending += ", seeker unreachable: " + t.SeekerUnreachable
+		}
+		fmt.Printf("- [%s] %s (%s) -> %s about %s, %d message(s), %s\n", t.ID, t.Request.RecruiterName,
+			t.Request.RecruiterCompany, t.Request.SeekerName, t.Request.Role, len(t.Messages), pending)
+	}
+}
+
+func reportsCommand(s *store.Store, args []string) {
+	fs := flag.NewFlagSet("reports", flag.ExitOnError)
+	status := fs.String("status", "open", "open, upheld, dismissed or all")
+	uphold := fs.String("uphold", "", "request ID whose report is upheld")
+	dismiss := fs.String("dismiss", "", "request ID whose report is dismissed")
+	adminID := fs.String("admin", "admin", "admin user id")
+	dsn := fs.String("dsn", os.Getenv("DATABASE_DSN"), "postgres connection string")
+	fs.Parse(args)
+
+	if *dsn == "" {
+		fs.Usage()
+		return
+	}
+
+	ctx := context.Background()
+	pool, err := database.Connect(ctx, *dsn)
+	if err != nil {
+		log.Fatalf("connect database: %v", err)
+	}
+	defer pool.Close()
+
+	// Reviewing never notifies anyone. Dismissing reports does not lift a
+	// suspension; approve the recruiter again with the admin command.
+	svc := contact.NewService(nil, contact.NewPostgresLogRepo(pool)).
+		WithAbuseReports(0, nil, nil, audit.NewPostgresLog(pool))
+
+	if *uphold != "" || *dismiss != "" {
+		id, decision := *uphold, contact.ReportUpheld
+		if *dismiss != "" {
+			id, decision = *dismiss, contact.ReportDismissed
+		}
+		entry, err := svc.ReviewReport(ctx, id, *adminID, decision)
+		if err != nil {
+			log.Fatalf("review report: %v", err)
+		}
+		fmt.Printf("Report on request %s %s.\n", entry.ID, entry.Report.Status)
+		fmt.Println(commands.AccessSummary(s, entry.Request.RecruiterID))
+		return
+	}
+
+	filter := contact.ReportStatus(*status)
+	if *status == "all" {
+		filter = ""
+	}
+	entries, err := svc.Reports(ctx, filter)
+	if err != nil {
+		log.Fatalf("list reports: %v", err)
+	}
+	fmt.Printf("%d report(s)\n", len(entries))
+	counts := map[string]int{}
+	for _, entry := range entries {
+		report := entry.Report
+		fmt.Printf("- [%s] %s %s by %s against %s (%s, recruiter %s) about %s [%s]",
+			entry.ID, report.CreatedAt.Format("2006-01-02 15:04"), report.Reason, entry.Request.SeekerContact,
+			entry.Request.RecruiterName, entry.Request.RecruiterCompany, entry.Request.RecruiterID, entry.Request.Role, report.Status)
+		if report.Details != "" {
+			fmt.Printf(": %s", report.Details)
+		}
+		fmt.Println()
+		if entry.Request.RecruiterID != "" {
+			counts[entry.Request.RecruiterID]++
+		}
+	}
+	recruiters := make([]string, 0, len(counts))
+	for recruiterID := range counts {
+		recruiters = append(recruiters, recruiterID)
+	}
+	sort.Strings(recruiters)
+	for _, recruiterID := range recruiters {
+		total, err := svc.ReportCount(ctx, recruiterID)
+		if err != nil {
+			log.Fatalf("count reports: %v", err)
+		}
+		fmt.Printf("%s; %d report(s) not dismissed\n", commands.AccessSummary(s, recruiterID), total)
+	}
+}
+
+func editPending(ctx context.Context, svc *broadcast.Service, id, reviewer string, changes map[string]string) (broadcast.BroadcastRecord, error) {
+	record, err := svc.Get(ctx, id)
+	if err != nil {
+		return record, err
+	}
+	posting, err := record.Job.WithFields(changes)
+	if err != nil {
+		return record, err
+	}
+	return svc.EditPendingBroadcast(ctx, id, reviewer, posting)
+}
+
 func splitSkills(input string) []string {
 	if input == "" {
 		return nil
@@ -0,0 +1,196 @@
+package contact
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Golangjobsuz/golangjobsuz/internal/store"
+)
+
+// Consent actions carried by the seeker's Accept/Decline buttons.
+const (
+	ActionAccept  = "accept"
+	ActionDecline = "decline"
+)
+
+// consentPrefix namespaces consent button data among other callbacks.
+const consentPrefix = "contact:"
+
+// ConsentNotifier is implemented by notifiers that can show the seeker
+// Accept/Decline buttons for a request, e.g. Telegram inline keyboards. The
+// buttons carry ConsentData for the request; without this interface the
+// seeker is asked to reply /accept <id> or /decline <id> instead.
+type ConsentNotifier interface {
+	AskSeeker(ctx context.Context, seekerContact, message, requestID string) error
+}
+
+// RecruiterNotifier is implemented by notifiers that can reach recruiters
+// directly. Otherwise the answer is sent with NotifySeeker addressed to the
+// recruiter's contact.
+type RecruiterNotifier interface {
+	NotifyRecruiter(ctx context.Context, recruiterContact, message string) error
+}
+
+// Directory looks up the contact details a seeker shares once they accept a
+// request, such as the e-mail and phone search.RedactContact hides.
+type Directory interface {
+	SeekerDetails(ctx context.Context, profileID string) (string, error)
+}
+
+// AccessGranter records that a recruiter may see a profile's contact details
+// until expiresAt, e.g. as a recruiter_access row. RevokeAccess withdraws the
+// grant made with expiresAt when the details could not be sent after all.
+type AccessGranter interface {
+	GrantAccess(ctx context.Context, recruiterID, profileID string, expiresAt time.Time) error
+	RevokeAccess(ctx context.Context, recruiterID, profileID string, expiresAt time.Time) error
+}
+
+// ConsentData returns the button payload for a consent action on a request.
+func ConsentData(action, requestID string) string {
+	return consentPrefix + action + ":" + requestID
+}
+
+// ParseConsentData splits a button payload built by ConsentData. ok is false
+// for payloads that are not consent actions.
+func ParseConsentData(data string) (action, requestID string, ok bool) {
+	rest, found := strings.CutPrefix(data, consentPrefix)
+	if !found {
+		return "", "", false
+	}
+	action, requestID, found = strings.Cut(rest, ":")
+	if !found || requestID == "" || (action != ActionAccept && action != ActionDecline) {
+		return "", "", false
+	}
+	return action, requestID, true
+}
+
+// WithConsent reveals the seeker's details to the recruiter when a request is
+// accepted. directory supplies the details, falling back to the request's
+// SeekerName and SeekerContact when nil; access, when set, is granted for
+// accessTTL (seven days when zero).
+func (s *Service) WithConsent(directory Directory, access AccessGranter, accessTTL time.Duration) *Service {
+	if accessTTL <= 0 {
+		accessTTL = 7 * 24 * time.Hour
+	}
+	s.directory = directory
+	s.access = access
+	s.accessTTL = accessTTL
+	return s
+}
+
+// Respond applies a seeker's Accept or Decline answer to a request.
+func (s *Service) Respond(ctx context.Context, action, requestID string) (LogEntry, error) {
+	switch action {
+	case ActionAccept:
+		return s.Accept(ctx, requestID)
+	case ActionDecline:
+		return s.Decline(ctx, requestID)
+	default:
+		return LogEntry{}, fmt.Errorf("unknown consent action %q", action)
+	}
+}
+
+// askSeeker delivers the request to the seeker, in their language, with a
+// way to answer it.
+func (s *Service) askSeeker(ctx context.Context, entry LogEntry) error {
+	message, err := s.seekerMessage(ctx, entry.ID, entry.Request)
+	if err != nil {
+		return fmt.Errorf("render contact request: %w", err)
+	}
+	if consent, ok := s.notifier.(ConsentNotifier); ok {
+		return consent.AskSeeker(ctx, entry.Request.SeekerContact, message, entry.ID)
+	}
+	return s.notifier.NotifySeeker(ctx, entry.Request.SeekerContact, message)
+}
+
+// reveal grants the recruiter access to the seeker's profile and sends them
+// the seeker's contact details. It runs before the acceptance is saved, so a
+// failed reveal leaves the request open to be accepted again; the access is
+// revoked when the recruiter cannot be told.
+func (s *Service) reveal(ctx context.Context, entry *LogEntry) error {
+	req := entry.Request
+	details := strings.TrimSpace(strings.Join([]string{req.SeekerName, req.SeekerContact}, " "))
+	if s.directory != nil && req.ProfileID != "" {
+		found, err := s.directory.SeekerDetails(ctx, req.ProfileID)
+		if err != nil {
+			return fmt.Errorf("look up seeker details: %w", err)
+		}
+		details = found
+	}
+
+	var granted *time.Time
+	if s.access != nil && req.RecruiterID != "" && req.ProfileID != "" {
+		expiresAt := s.clock().Add(s.accessTTL)
+		if err := s.access.GrantAccess(ctx, req.RecruiterID, req.ProfileID, expiresAt); err != nil {
+			return fmt.Errorf("grant recruiter access: %w", err)
+		}
+		granted = &expiresAt
+	}
+
+	message := fmt.Sprintf("Good news: the candidate accepted your request about %s. Contact details: %s", req.Role, details)
+	if err := s.notifyRecruiter(ctx, req, message); err != nil {
+		err = fmt.Errorf("notify recruiter: %w", err)
+		if granted != nil {
+			if revokeErr := s.access.RevokeAccess(ctx, req.RecruiterID, req.ProfileID, *granted); revokeErr != nil {
+				err = errors.Join(err, fmt.Errorf("revoke recruiter access: %w", revokeErr))
+			}
+		}
+		return err
+	}
+	entry.AccessExpiresAt = granted
+	return nil
+}
+
+// notifyRecruiter tells the recruiter how the seeker answered.
+func (s *Service) notifyRecruiter(ctx context.Context, req Request, message string) error {
+	if req.RecruiterContact == "" {
+		return s.notifier.NotifyAdmin(ctx, "Recruiter "+req.RecruiterName+" has no contact details: "+message)
+	}
+	if recruiters, ok := s.notifier.(RecruiterNotifier); ok {
+		return recruiters.NotifyRecruiter(ctx, req.RecruiterContact, message)
+	}
+	return s.notifier.NotifySeeker(ctx, req.RecruiterContact, message)
+}
+
+// StoreDirectory reads seeker details and preferred languages from the
+// profiles in a store.Store.
+type StoreDirectory struct {
+	Store *store.Store
+}
+
+// SeekerDetails implements Directory.
+func (d StoreDirectory) SeekerDetails(_ context.Context, profileID string) (string, error) {
+	profile, ok := d.profile(profileID)
+	if !ok {
+		return "", fmt.Errorf("profile %s not found", profileID)
+	}
+	var channels []string
+	for _, value := range []string{profile.ContactEmail, profile.ContactPhone} {
+		if value != "" {
+			channels = append(channels, value)
+		}
+	}
+	if len(channels) == 0 {
+		return "", fmt.Errorf("profile %s has no contact details", profileID)
+	}
+	return profile.Name + ": " + strings.Join(channels, ", "), nil
+}
+
+// SeekerLanguage implements LanguageDirectory.
+func (d StoreDirectory) SeekerLanguage(_ context.Context, profileID string) (string, error) {
+	profile, ok := d.profile(profileID)
+	if !ok {
+		return "", fmt.Errorf("profile %s not found", profileID)
+	}
+	return profile.Language, nil
+}
+
+func (d StoreDirectory) profile(profileID string) (profile store.Profile, ok bool) {
+	d.Store.View(func(s *store.Store) {
+		profile, ok = s.Profiles[profileID]
+	})
+	return profile, ok
+}
@@ -0,0 +1,284 @@
+package contact
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresLogRepo stores contact requests in the contact_requests table so
+// they survive restarts.
+type PostgresLogRepo struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresLogRepo constructs a log backed by the given pool.
+func NewPostgresLogRepo(pool *pgxpool.Pool) *PostgresLogRepo {
+	return &PostgresLogRepo{pool: pool}
+}
+
+const entryColumns = `request_id, request, status, delivered, via_admin, error, created_at, updated_at, access_expires_at, fallback_reason, report`
+
+// Save inserts the entry or updates the row with the same request ID.
+func (r *PostgresLogRepo) Save(ctx context.Context, entry LogEntry) error {
+	_, err := r.pool.Exec(ctx, `
+INSERT INTO contact_requests (`+entryColumns+`)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+ON CONFLICT (request_id) DO UPDATE SET
+    request = EXCLUDED.request,
+    status = EXCLUDED.status,
+    delivered = EXCLUDED.delivered,
+    via_admin = EXCLUDED.via_admin,
+    error = EXCLUDED.error,
+    updated_at = EXCLUDED.updated_at,
+    access_expires_at = EXCLUDED.access_expires_at,
+    fallback_reason = EXCLUDED.fallback_reason,
+    report = EXCLUDED.report`,
+		entry.ID,
+		entry.Request,
+		string(entry.Status),
+		entry.Delivered,
+		entry.ViaAdmin,
+		entry.Error,
+		entry.Timestamp,
+		entry.UpdatedAt,
+		entry.AccessExpiresAt,
+		entry.FallbackReason,
+		entry.Report,
+	)
+	if err != nil {
+		return fmt.Errorf("save contact request %s: %w", entry.ID, err)
+	}
+	return nil
+}
+
+// List returns all contact requests, oldest first.
+func (r *PostgresLogRepo) List(ctx context.Context) ([]LogEntry, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+entryColumns+` FROM contact_requests ORDER BY created_at, request_id`)
+	if err != nil {
+		return nil, fmt.Errorf("query contact requests: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []LogEntry{}
+	for rows.Next() {
+		entry, err := scanEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan contact request: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read contact requests: %w", err)
+	}
+	return entries, nil
+}
+
+// ListByRecruiterSince implements RecruiterHistory, returning the requests
+// the recruiter sent after since, oldest first.
+func (r *PostgresLogRepo) ListByRecruiterSince(ctx context.Context, recruiterID string, since time.Time) ([]LogEntry, error) {
+	rows, err := r.pool.Query(ctx, `
+SELECT `+entryColumns+` FROM contact_requests
+WHERE request->>'RecruiterID' = $1 AND created_at > $2
+ORDER BY created_at, request_id`, recruiterID, since)
+	if err != nil {
+		return nil, fmt.Errorf("query contact requests of recruiter %s: %w", recruiterID, err)
+	}
+	defer rows.Close()
+
+	entries := []LogEntry{}
+	for rows.Next() {
+		entry, err := scanEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan contact request: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read contact requests: %w", err)
+	}
+	return entries, nil
+}
+
+// Get returns the request with the given ID or ErrNotFound.
+func (r *PostgresLogRepo) Get(ctx context.Context, id string) (LogEntry, error) {
+	row := r.pool.QueryRow(ctx, `SELECT `+entryColumns+` FROM contact_requests WHERE request_id = $1`, id)
+	entry, err := scanEntry(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return LogEntry{}, ErrNotFound
+	}
+	if err != nil {
+		return LogEntry{}, fmt.Errorf("get contact request %s: %w", id, err)
+	}
+	return entry, nil
+}
+
+func scanEntry(row pgx.Row) (LogEntry, error) {
+	var (
+		entry  LogEntry
+		status string
+	)
+	err := row.Scan(
+		&entry.ID,
+		&entry.Request,
+		&status,
+		&entry.Delivered,
+		&entry.ViaAdmin,
+		&entry.Error,
+		&entry.Timestamp,
+		&entry.UpdatedAt,
+		&entry.AccessExpiresAt,
+		&entry.FallbackReason,
+		&entry.Report,
+	)
+	if err != nil {
+		return LogEntry{}, err
+	}
+	entry.Status = RequestStatus(status)
+	return entry, nil
+}
+
+// PostgresAccess grants recruiters access to profiles through the
+// recruiter_access table.
+type PostgresAccess struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresAccess constructs an AccessGranter backed by the given pool.
+func NewPostgresAccess(pool *pgxpool.Pool) *PostgresAccess {
+	return &PostgresAccess{pool: pool}
+}
+
+// GrantAccess implements AccessGranter. recruiterID is the recruiter's
+// platform user ID; granting again moves the expiry.
+func (a *PostgresAccess) GrantAccess(ctx context.Context, recruiterID, profileID string, expiresAt time.Time) error {
+	tag, err := a.pool.Exec(ctx, `
+INSERT INTO recruiter_access (recruiter_user_id, profile_id, expires_at)
+SELECT id, $2::bigint, $3 FROM users WHERE platform_user_id = $1
+ON CONFLICT (recruiter_user_id, profile_id) DO UPDATE SET expires_at = EXCLUDED.expires_at`,
+		recruiterID, profileID, expiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("grant access to profile %s: %w", profileID, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("grant access to profile %s: recruiter %s is not a registered user", profileID, recruiterID)
+	}
+	return nil
+}
+
+// RevokeAccess implements AccessGranter. Only the grant that still expires at
+// expiresAt is removed, so a later grant is kept.
+func (a *PostgresAccess) RevokeAccess(ctx context.Context, recruiterID, profileID string, expiresAt time.Time) error {
+	_, err := a.pool.Exec(ctx, `
+DELETE FROM recruiter_access ra
+USING users u
+WHERE ra.recruiter_user_id = u.id AND u.platform_user_id = $1 AND ra.profile_id = $2::bigint AND ra.expires_at = $3`,
+		recruiterID, profileID, expiresAt,
+	)
+	if err != nil {
+		return fmt.Errorf("revoke access to profile %s: %w", profileID, err)
+	}
+	return nil
+}
+
+// PostgresThreadRepo stores relay threads in the contact_relay_threads table.
+type PostgresThreadRepo struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresThreadRepo constructs a thread store backed by the given pool.
+func NewPostgresThreadRepo(pool *pgxpool.Pool) *PostgresThreadRepo {
+	return &PostgresThreadRepo{pool: pool}
+}
+
+const threadColumns = `thread_id, request_id, request, status, messages, created_at, updated_at, seeker_unreachable`
+
+// Save implements ThreadRepo.
+func (r *PostgresThreadRepo) Save(ctx context.Context, thread Thread) error {
+	messages := thread.Messages
+	if messages == nil {
+		messages = []ThreadMessage{}
+	}
+	_, err := r.pool.Exec(ctx, `
+INSERT INTO contact_relay_threads (`+threadColumns+`)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (thread_id) DO UPDATE SET
+    status = EXCLUDED.status,
+    messages = EXCLUDED.messages,
+    updated_at = EXCLUDED.updated_at`,
+		thread.ID,
+		thread.RequestID,
+		thread.Request,
+		string(thread.Status),
+		messages,
+		thread.CreatedAt,
+		thread.UpdatedAt,
+		thread.SeekerUnreachable,
+	)
+	if err != nil {
+		return fmt.Errorf("save relay thread %s: %w", thread.ID, err)
+	}
+	return nil
+}
+
+// Get implements ThreadRepo.
+func (r *PostgresThreadRepo) Get(ctx context.Context, id string) (Thread, error) {
+	row := r.pool.QueryRow(ctx, `SELECT `+threadColumns+` FROM contact_relay_threads WHERE thread_id = $1`, id)
+	thread, err := scanThread(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Thread{}, ErrThreadNotFound
+	}
+	if err != nil {
+		return Thread{}, fmt.Errorf("get relay thread %s: %w", id, err)
+	}
+	return thread, nil
+}
+
+// ListOpen implements ThreadRepo, oldest thread first.
+func (r *PostgresThreadRepo) ListOpen(ctx context.Context) ([]Thread, error) {
+	rows, err := r.pool.Query(ctx, `SELECT `+threadColumns+` FROM contact_relay_threads WHERE status = $1 ORDER BY created_at, thread_id`, string(ThreadOpen))
+	if err != nil {
+		return nil, fmt.Errorf("query relay threads: %w", err)
+	}
+	defer rows.Close()
+
+	threads := []Thread{}
+	for rows.Next() {
+		thread, err := scanThread(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan relay thread: %w", err)
+		}
+		threads = append(threads, thread)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read relay threads: %w", err)
+	}
+	return threads, nil
+}
+
+func scanThread(row pgx.Row) (Thread, error) {
+	var (
+		thread Thread
+		status string
+	)
+	err := row.Scan(
+		&thread.ID,
+		&thread.RequestID,
+		&thread.Request,
+		&status,
+		&thread.Messages,
+		&thread.CreatedAt,
+		&thread.UpdatedAt,
+		&thread.SeekerUnreachable,
+	)
+	if err != nil {
+		return Thread{}, err
+	}
+	thread.Status = ThreadStatus(status)
+	return thread, nil
+}
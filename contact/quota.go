@@ -0,0 +1,277 @@
+package contact
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Golangjobsuz/golangjobsuz/internal/audit"
+	"github.com/Golangjobsuz/golangjobsuz/internal/store"
+)
+
+// ErrRecruiterRequired is returned when limits are enforced and a request
+// does not say which recruiter sent it.
+var ErrRecruiterRequired = errors.New("recruiter ID is required")
+
+// Audit actions written when a request is refused.
+const (
+	AuditQuotaExceeded    = "contact.quota_exceeded"
+	AuditCooldown         = "contact.cooldown"
+	AuditRecruiterBlocked = "contact.recruiter_blocked"
+)
+
+// QuotaPolicy limits how many contact requests a recruiter may send. Zero
+// values disable the corresponding limit.
+type QuotaPolicy struct {
+	Daily  int
+	Weekly int
+	// Cooldown is the minimum time between two requests from the same
+	// recruiter to the same seeker.
+	Cooldown time.Duration
+}
+
+// DefaultQuotaPolicy allows 20 requests a day, 60 a week and one request per
+// seeker every 14 days.
+func DefaultQuotaPolicy() QuotaPolicy {
+	return QuotaPolicy{Daily: 20, Weekly: 60, Cooldown: 14 * 24 * time.Hour}
+}
+
+// RecruiterDirectory reports a recruiter's access status, such as
+// store.RecruiterAccess.Status. Only "approved" recruiters may send requests.
+type RecruiterDirectory interface {
+	RecruiterStatus(ctx context.Context, recruiterID string) (string, error)
+}
+
+// RecruiterHistory is implemented by log repos that can list one
+// recruiter's recent requests without loading the whole log, such as
+// PostgresLogRepo. checkLimits falls back to LogRepo.List otherwise.
+type RecruiterHistory interface {
+	ListByRecruiterSince(ctx context.Context, recruiterID string, since time.Time) ([]LogEntry, error)
+}
+
+// QuotaError is returned when a recruiter has used up a request quota.
+type QuotaError struct {
+	RecruiterID string
+	// Period is "daily" or "weekly".
+	Period string
+	Limit  int
+	// RetryAt is when the oldest counted request leaves the window.
+	RetryAt time.Time
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("recruiter %s reached the %s limit of %d contact requests; try again after %s",
+		e.RecruiterID, e.Period, e.Limit, e.RetryAt.Format(time.RFC3339))
+}
+
+// CooldownError is returned when a recruiter contacts the same seeker again
+// too soon.
+type CooldownError struct {
+	RecruiterID string
+	Seeker      string
+	RetryAt     time.Time
+}
+
+func (e *CooldownError) Error() string {
+	return fmt.Sprintf("recruiter %s already contacted %s; try again after %s",
+		e.RecruiterID, e.Seeker, e.RetryAt.Format(time.RFC3339))
+}
+
+// RecruiterBlockedError is returned for recruiters that are not approved.
+type RecruiterBlockedError struct {
+	RecruiterID string
+	Status      string
+}
+
+func (e *RecruiterBlockedError) Error() string {
+	status := e.Status
+	if status == "" {
+		status = "not approved"
+	}
+	return fmt.Sprintf("recruiter %s cannot send contact requests: %s", e.RecruiterID, status)
+}
+
+// WithQuota enforces policy and recruiter approval on HandleRequest. Refused
+// requests are not sent or saved; they are written to log when it is not nil.
+func (s *Service) WithQuota(policy QuotaPolicy, recruiters RecruiterDirectory, log audit.Log) *Service {
+	s.quota = &policy
+	s.recruiters = recruiters
+	if log != nil {
+		s.audit = log
+	}
+	return s
+}
+
+// checkLimits refuses requests from unapproved recruiters and requests over
+// quota or within the cooldown, recording an audit entry for each refusal.
+func (s *Service) checkLimits(ctx context.Context, req Request) error {
+	if s.quota == nil && s.recruiters == nil {
+		return nil
+	}
+	if strings.TrimSpace(req.RecruiterID) == "" {
+		return ErrRecruiterRequired
+	}
+
+	if s.recruiters != nil {
+		status, err := s.recruiters.RecruiterStatus(ctx, req.RecruiterID)
+		if err != nil {
+			return fmt.Errorf("look up recruiter %s: %w", req.RecruiterID, err)
+		}
+		if status != "approved" {
+			return s.refuse(ctx, AuditRecruiterBlocked, req, &RecruiterBlockedError{RecruiterID: req.RecruiterID, Status: status})
+		}
+	}
+	if s.quota == nil {
+		return nil
+	}
+
+	now := s.clock()
+	day, week := now.Add(-24*time.Hour), now.Add(-7*24*time.Hour)
+	since := week
+	if cooldown := now.Add(-s.quota.Cooldown); cooldown.Before(since) {
+		since = cooldown
+	}
+	entries, err := s.recruiterEntries(ctx, req.RecruiterID, since)
+	if err != nil {
+		return err
+	}
+	var (
+		daily, weekly []time.Time
+		lastToSeeker  time.Time
+	)
+	for _, entry := range entries {
+		if entry.Request.RecruiterID != req.RecruiterID {
+			continue
+		}
+		if entry.Timestamp.After(week) {
+			weekly = append(weekly, entry.Timestamp)
+		}
+		if entry.Timestamp.After(day) {
+			daily = append(daily, entry.Timestamp)
+		}
+		if seekerKey(req) != "" && seekerKey(entry.Request) == seekerKey(req) && entry.Timestamp.After(lastToSeeker) {
+			lastToSeeker = entry.Timestamp
+		}
+	}
+
+	policy := *s.quota
+	if policy.Cooldown > 0 && !lastToSeeker.IsZero() && now.Before(lastToSeeker.Add(policy.Cooldown)) {
+		return s.refuse(ctx, AuditCooldown, req, &CooldownError{RecruiterID: req.RecruiterID, Seeker: seekerKey(req), RetryAt: lastToSeeker.Add(policy.Cooldown)})
+	}
+	if policy.Daily > 0 && len(daily) >= policy.Daily {
+		return s.refuse(ctx, AuditQuotaExceeded, req, &QuotaError{RecruiterID: req.RecruiterID, Period: "daily", Limit: policy.Daily, RetryAt: earliest(daily).Add(24 * time.Hour)})
+	}
+	if policy.Weekly > 0 && len(weekly) >= policy.Weekly {
+		return s.refuse(ctx, AuditQuotaExceeded, req, &QuotaError{RecruiterID: req.RecruiterID, Period: "weekly", Limit: policy.Weekly, RetryAt: earliest(weekly).Add(7 * 24 * time.Hour)})
+	}
+	return nil
+}
+
+// recruiterEntries returns the requests recruiterID sent after since. Repos
+// that do not implement RecruiterHistory may return other entries too.
+func (s *Service) recruiterEntries(ctx context.Context, recruiterID string, since time.Time) ([]LogEntry, error) {
+	if history, ok := s.repo.(RecruiterHistory); ok {
+		entries, err := history.ListByRecruiterSince(ctx, recruiterID, since)
+		if err != nil {
+			return nil, fmt.Errorf("list requests of recruiter %s: %w", recruiterID, err)
+		}
+		return entries, nil
+	}
+	entries, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list contact log: %w", err)
+	}
+	return entries, nil
+}
+
+// refuse audits a refused request and returns refusal. An audit failure is
+// joined to the refusal so the request is refused either way.
+func (s *Service) refuse(ctx context.Context, action string, req Request, refusal error) error {
+	if s.audit == nil {
+		return refusal
+	}
+	err := s.audit.Record(ctx, audit.Entry{
+		ActorID:    req.RecruiterID,
+		Action:     action,
+		TargetType: "contact_request",
+		TargetID:   seekerKey(req),
+		Metadata: map[string]any{
+			"recruiter": req.RecruiterName,
+			"company":   req.RecruiterCompany,
+			"role":      req.Role,
+			"reason":    refusal.Error(),
+		},
+		CreatedAt: s.clock(),
+	})
+	if err != nil {
+		return errors.Join(refusal, fmt.Errorf("audit %s: %w", action, err))
+	}
+	return refusal
+}
+
+// seekerKey identifies the seeker of a request for the cooldown.
+func seekerKey(req Request) string {
+	if req.ProfileID != "" {
+		return req.ProfileID
+	}
+	return strings.ToLower(strings.TrimSpace(req.SeekerContact))
+}
+
+func earliest(times []time.Time) time.Time {
+	first := times[0]
+	for _, t := range times[1:] {
+		if t.Before(first) {
+			first = t
+		}
+	}
+	return first
+}
+
+// StoreRecruiters reports recruiter statuses from store.RecruiterAccess and
+// suspends recruiters there.
+type StoreRecruiters struct {
+	Store *store.Store
+}
+
+// RecruiterStatus implements RecruiterDirectory. Unknown recruiters are
+// reported as "pending".
+func (r StoreRecruiters) RecruiterStatus(_ context.Context, recruiterID string) (string, error) {
+	var access store.RecruiterAccess
+	r.Store.View(func(s *store.Store) {
+		access = s.RecruiterAccess[recruiterID]
+	})
+	if access.Status == "" {
+		return "pending", nil
+	}
+	return access.Status, nil
+}
+
+// SuspendRecruiter implements RecruiterSuspender. It updates the saved store,
+// keeping the recruiter's other fields and appending reason to their notes.
+func (r StoreRecruiters) SuspendRecruiter(_ context.Context, recruiterID, reason string) (bool, error) {
+	suspended := false
+	err := r.Store.Update(func(s *store.Store) error {
+		access, ok := s.RecruiterAccess[recruiterID]
+		if ok && (access.Status == "suspended" || access.Status == "banned") {
+			return nil
+		}
+		access.UserID = recruiterID
+		access.Status = "suspended"
+		access.UpdatedAt = time.Now().UTC()
+		access.UpdatedBy = "system"
+		if access.Notes == "" {
+			access.Notes = reason
+		} else if reason != "" {
+			access.Notes += "; " + reason
+		}
+		s.RecruiterAccess[recruiterID] = access
+		suspended = true
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return suspended, nil
+}
@@ -0,0 +1,405 @@
+package contact
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Golangjobsuz/golangjobsuz/internal/audit"
+)
+
+var (
+	// ErrThreadNotFound is returned when a relay thread does not exist.
+	ErrThreadNotFound = errors.New("relay thread not found")
+	// ErrThreadClosed is returned when a closed thread receives a message or
+	// review decision.
+	ErrThreadClosed = errors.New("relay thread is closed")
+	// ErrNothingPending is returned when an admin reviews a thread without a
+	// message waiting for review.
+	ErrNothingPending = errors.New("no relay message waiting for review")
+)
+
+// Audit actions written for relay review decisions.
+const (
+	AuditRelayForward = "contact.relay_forward"
+	AuditRelayReject  = "contact.relay_reject"
+	AuditRelayClose   = "contact.relay_close"
+)
+
+// Party is a participant of a relay thread.
+type Party string
+
+const (
+	PartyRecruiter Party = "recruiter"
+	PartySeeker    Party = "seeker"
+)
+
+// other returns the party a message from p is relayed to.
+func (p Party) other() Party {
+	if p == PartyRecruiter {
+		return PartySeeker
+	}
+	return PartyRecruiter
+}
+
+// MessageStatus is the review state of a relayed message.
+type MessageStatus string
+
+const (
+	MessagePending   MessageStatus = "pending"
+	MessageForwarded MessageStatus = "forwarded"
+	MessageRejected  MessageStatus = "rejected"
+)
+
+// ThreadStatus is the state of a relay thread.
+type ThreadStatus string
+
+const (
+	ThreadOpen   ThreadStatus = "open"
+	ThreadClosed ThreadStatus = "closed"
+)
+
+// ThreadMessage is one message relayed through the admins. Forwarded holds
+// the text actually sent, which differs from Text when an admin edited it.
+type ThreadMessage struct {
+	From       Party         `json:"from"`
+	Text       string        `json:"text"`
+	Forwarded  string        `json:"forwarded,omitempty"`
+	Status     MessageStatus `json:"status"`
+	ReviewedBy string        `json:"reviewedBy,omitempty"`
+	Reason     string        `json:"reason,omitempty"`
+	CreatedAt  time.Time     `json:"createdAt"`
+	ReviewedAt *time.Time    `json:"reviewedAt,omitempty"`
+}
+
+// Thread is an admin-relayed conversation between a recruiter and a seeker
+// who never see each other's identity.
+type Thread struct {
+	ID        string
+	RequestID string
+	Request   Request
+	Status    ThreadStatus
+	Messages  []ThreadMessage
+	// SeekerUnreachable is why the seeker could not be reached directly when
+	// the thread was opened. Messages forwarded to them fail until they can be.
+	SeekerUnreachable string
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// Pending returns the index of the oldest message waiting for review, or -1.
+func (t Thread) Pending() int {
+	for i, message := range t.Messages {
+		if message.Status == MessagePending {
+			return i
+		}
+	}
+	return -1
+}
+
+// ThreadRepo persists relay threads. Save inserts the thread or replaces the
+// one with the same ID.
+type ThreadRepo interface {
+	Save(ctx context.Context, thread Thread) error
+	Get(ctx context.Context, id string) (Thread, error)
+	ListOpen(ctx context.Context) ([]Thread, error)
+}
+
+// WithRelayInbox turns admin-relayed requests into two-way threads stored in
+// threads: every message is held for an admin to forward, edit or reject.
+// Review decisions are written to log when it is not nil.
+func (s *Service) WithRelayInbox(threads ThreadRepo, log audit.Log) *Service {
+	s.threads = threads
+	if log != nil {
+		s.audit = log
+	}
+	return s
+}
+
+// OpenThreads lists the relay threads that are still open.
+func (s *Service) OpenThreads(ctx context.Context) ([]Thread, error) {
+	if s.threads == nil {
+		return nil, errors.New("relay inbox is not configured")
+	}
+	threads, err := s.threads.ListOpen(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list relay threads: %w", err)
+	}
+	return threads, nil
+}
+
+// GetThread returns the relay thread with the given ID.
+func (s *Service) GetThread(ctx context.Context, id string) (Thread, error) {
+	if s.threads == nil {
+		return Thread{}, errors.New("relay inbox is not configured")
+	}
+	return s.threads.Get(ctx, id)
+}
+
+// startThread opens a thread for an admin-relayed request, holding the
+// recruiter's introduction for review, and returns the admin notification.
+func (s *Service) startThread(ctx context.Context, entry LogEntry) (string, error) {
+	req := entry.Request
+	intro := "A recruiter would like to talk to you about " + req.Role + "."
+	if notes := strings.TrimSpace(req.Notes); notes != "" {
+		intro += " " + notes
+	}
+
+	now := s.clock()
+	thread := Thread{
+		ID:                entry.ID,
+		RequestID:         entry.ID,
+		Request:           req,
+		Status:            ThreadOpen,
+		Messages:          []ThreadMessage{{From: PartyRecruiter, Text: intro, Status: MessagePending, CreatedAt: now}},
+		CreatedAt:         now,
+		UpdatedAt:         now,
+		SeekerUnreachable: entry.FallbackReason,
+	}
+	if err := s.threads.Save(ctx, thread); err != nil {
+		return "", fmt.Errorf("save relay thread: %w", err)
+	}
+	return formatMessage(entry.ID, req) + "\n\n" + reviewPrompt(thread, 0), nil
+}
+
+// ReplyInThread adds a message from one party of a thread and asks the
+// admins to review it.
+func (s *Service) ReplyInThread(ctx context.Context, threadID string, from Party, text string) (Thread, error) {
+	thread, err := s.openThread(ctx, threadID)
+	if err != nil {
+		return thread, err
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return thread, errors.New("relay message is empty")
+	}
+
+	now := s.clock()
+	thread.Messages = append(thread.Messages, ThreadMessage{From: from, Text: text, Status: MessagePending, CreatedAt: now})
+	thread.UpdatedAt = now
+	if err := s.threads.Save(ctx, thread); err != nil {
+		return thread, fmt.Errorf("save relay thread: %w", err)
+	}
+	if err := s.notifier.NotifyAdmin(ctx, reviewPrompt(thread, len(thread.Messages)-1)); err != nil {
+		return thread, fmt.Errorf("notify admin: %w", err)
+	}
+	return thread, nil
+}
+
+// ForwardRelay sends the oldest pending message of a thread to the other
+// party. A non-empty edited text replaces the message as sent. The message is
+// saved as forwarded before it is sent, so a repeated review cannot send it
+// twice, and goes back to pending when sending fails.
+func (s *Service) ForwardRelay(ctx context.Context, threadID, adminID, edited string) (Thread, error) {
+	thread, i, err := s.pendingMessage(ctx, threadID, adminID)
+	if err != nil {
+		return thread, err
+	}
+
+	message := &thread.Messages[i]
+	pending := *message
+	text := message.Text
+	if edited = strings.TrimSpace(edited); edited != "" {
+		text = edited
+	}
+
+	now := s.clock()
+	message.Status = MessageForwarded
+	message.Forwarded = text
+	message.ReviewedBy = adminID
+	message.ReviewedAt = &now
+	thread.UpdatedAt = now
+	if err := s.threads.Save(ctx, thread); err != nil {
+		return thread, fmt.Errorf("save relay thread: %w", err)
+	}
+
+	to := message.From.other()
+	relayed := fmt.Sprintf("Message from the %s via the Golangjobsuz admins:\n%s\n\nReply with /reply %s <text>.", message.From, text, thread.ID)
+	if err := s.notifyParty(ctx, thread.Request, to, relayed); err != nil {
+		err = fmt.Errorf("relay to %s: %w", to, err)
+		*message = pending
+		thread.UpdatedAt = s.clock()
+		if saveErr := s.threads.Save(ctx, thread); saveErr != nil {
+			err = errors.Join(err, fmt.Errorf("save relay thread: %w", saveErr))
+		}
+		return thread, err
+	}
+
+	metadata := map[string]any{"from": string(message.From), "text": message.Text, "forwarded": text}
+	if err := s.auditRelay(ctx, AuditRelayForward, adminID, thread, metadata); err != nil {
+		return thread, err
+	}
+	return thread, nil
+}
+
+// RejectRelay drops the oldest pending message of a thread and tells its
+// author it was not forwarded.
+func (s *Service) RejectRelay(ctx context.Context, threadID, adminID, reason string) (Thread, error) {
+	thread, i, err := s.pendingMessage(ctx, threadID, adminID)
+	if err != nil {
+		return thread, err
+	}
+
+	message := &thread.Messages[i]
+	metadata := map[string]any{"from": string(message.From), "text": message.Text, "reason": reason}
+	if err := s.auditRelay(ctx, AuditRelayReject, adminID, thread, metadata); err != nil {
+		return thread, err
+	}
+
+	now := s.clock()
+	message.Status = MessageRejected
+	message.Reason = reason
+	message.ReviewedBy = adminID
+	message.ReviewedAt = &now
+	thread.UpdatedAt = now
+	if err := s.threads.Save(ctx, thread); err != nil {
+		return thread, fmt.Errorf("save relay thread: %w", err)
+	}
+
+	notice := "Your message was not forwarded by the admins."
+	if reason != "" {
+		notice += " Reason: " + reason
+	}
+	if err := s.notifyParty(ctx, thread.Request, message.From, notice); err != nil {
+		return thread, fmt.Errorf("notify %s: %w", message.From, err)
+	}
+	return thread, nil
+}
+
+// CloseRelay closes a thread; pending messages are left unsent.
+func (s *Service) CloseRelay(ctx context.Context, threadID, adminID string) (Thread, error) {
+	thread, err := s.openThread(ctx, threadID)
+	if err != nil {
+		return thread, err
+	}
+	if strings.TrimSpace(adminID) == "" {
+		return thread, errors.New("admin ID is required")
+	}
+	if err := s.auditRelay(ctx, AuditRelayClose, adminID, thread, nil); err != nil {
+		return thread, err
+	}
+
+	thread.Status = ThreadClosed
+	thread.UpdatedAt = s.clock()
+	if err := s.threads.Save(ctx, thread); err != nil {
+		return thread, fmt.Errorf("save relay thread: %w", err)
+	}
+	return thread, nil
+}
+
+func (s *Service) openThread(ctx context.Context, threadID string) (Thread, error) {
+	thread, err := s.GetThread(ctx, threadID)
+	if err != nil {
+		return Thread{}, fmt.Errorf("load relay thread %s: %w", threadID, err)
+	}
+	if thread.Status != ThreadOpen {
+		return thread, fmt.Errorf("%w: %s", ErrThreadClosed, threadID)
+	}
+	return thread, nil
+}
+
+func (s *Service) pendingMessage(ctx context.Context, threadID, adminID string) (Thread, int, error) {
+	if strings.TrimSpace(adminID) == "" {
+		return Thread{}, -1, errors.New("admin ID is required")
+	}
+	thread, err := s.openThread(ctx, threadID)
+	if err != nil {
+		return thread, -1, err
+	}
+	i := thread.Pending()
+	if i < 0 {
+		return thread, -1, fmt.Errorf("%w: thread %s", ErrNothingPending, threadID)
+	}
+	return thread, i, nil
+}
+
+// notifyParty delivers a relayed message without revealing the sender.
+func (s *Service) notifyParty(ctx context.Context, req Request, to Party, message string) error {
+	if to == PartyRecruiter {
+		return s.notifyRecruiter(ctx, req, message)
+	}
+	if req.SeekerContact == "" {
+		return errors.New("seeker has no contact details")
+	}
+	return s.notifier.NotifySeeker(ctx, req.SeekerContact, message)
+}
+
+func (s *Service) auditRelay(ctx context.Context, action, adminID string, thread Thread, metadata map[string]any) error {
+	if s.audit == nil {
+		return nil
+	}
+	err := s.audit.Record(ctx, audit.Entry{
+		ActorID:    adminID,
+		Action:     action,
+		TargetType: "relay_thread",
+		TargetID:   thread.ID,
+		Metadata:   metadata,
+		CreatedAt:  s.clock(),
+	})
+	if err != nil {
+		return fmt.Errorf("audit %s of thread %s: %w", action, thread.ID, err)
+	}
+	return nil
+}
+
+// reviewPrompt asks the admins to review message i of a thread.
+func reviewPrompt(thread Thread, i int) string {
+	message := thread.Messages[i]
+	return fmt.Sprintf(
+		"Relay thread %s: message from the %s waiting for review:\n%s\n\n"+
+			"Reply /relay_forward %s [edited text], /relay_reject %s [reason] or /relay_close %s.",
+		thread.ID, message.From, message.Text, thread.ID, thread.ID, thread.ID,
+	)
+}
+
+// MemoryThreadRepo keeps relay threads in memory for tests and local runs.
+type MemoryThreadRepo struct {
+	mu      sync.Mutex
+	threads map[string]Thread
+}
+
+// NewMemoryThreadRepo constructs an empty in-memory thread store.
+func NewMemoryThreadRepo() *MemoryThreadRepo {
+	return &MemoryThreadRepo{threads: make(map[string]Thread)}
+}
+
+// Save implements ThreadRepo.
+func (r *MemoryThreadRepo) Save(_ context.Context, thread Thread) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	thread.Messages = append([]ThreadMessage(nil), thread.Messages...)
+	r.threads[thread.ID] = thread
+	return nil
+}
+
+// Get implements ThreadRepo.
+func (r *MemoryThreadRepo) Get(_ context.Context, id string) (Thread, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	thread, ok := r.threads[id]
+	if !ok {
+		return Thread{}, ErrThreadNotFound
+	}
+	thread.Messages = append([]ThreadMessage(nil), thread.Messages...)
+	return thread, nil
+}
+
+// ListOpen implements ThreadRepo, oldest thread first.
+func (r *MemoryThreadRepo) ListOpen(_ context.Context) ([]Thread, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var open []Thread
+	for _, thread := range r.threads {
+		if thread.Status == ThreadOpen {
+			open = append(open, thread)
+		}
+	}
+	sort.Slice(open, func(i, j int) bool {
+		return open[i].CreatedAt.Before(open[j].CreatedAt)
+	})
+	return open, nil
+}
@@ -0,0 +1,258 @@
+package contact
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Golangjobsuz/golangjobsuz/internal/audit"
+)
+
+var (
+	// ErrAlreadyReported is returned when a seeker reports the same request
+	// twice.
+	ErrAlreadyReported = errors.New("contact request already reported")
+	// ErrNoReport is returned when reviewing a request nobody reported.
+	ErrNoReport = errors.New("contact request has no report")
+)
+
+// Audit actions written for seeker reports.
+const (
+	AuditReported           = "contact.reported"
+	AuditReportReviewed     = "contact.report_reviewed"
+	AuditRecruiterSuspended = "contact.recruiter_suspended"
+)
+
+// DefaultReportThreshold is the number of open or upheld reports after which
+// a recruiter is suspended.
+const DefaultReportThreshold = 3
+
+// ReportReason is why a seeker reported a recruiter.
+type ReportReason string
+
+const (
+	ReportSpam  ReportReason = "spam"
+	ReportAbuse ReportReason = "abuse"
+)
+
+// ReportStatus is the admin review state of a report.
+type ReportStatus string
+
+const (
+	ReportOpen      ReportStatus = "open"
+	ReportUpheld    ReportStatus = "upheld"
+	ReportDismissed ReportStatus = "dismissed"
+)
+
+// Report is a seeker's complaint about the recruiter of a request. It is
+// stored on the request's LogEntry.
+type Report struct {
+	Reason     ReportReason `json:"reason"`
+	Details    string       `json:"details,omitempty"`
+	Status     ReportStatus `json:"status"`
+	CreatedAt  time.Time    `json:"created_at"`
+	ReviewedBy string       `json:"reviewed_by,omitempty"`
+	ReviewedAt *time.Time   `json:"reviewed_at,omitempty"`
+}
+
+// RecruiterSuspender suspends recruiters that collected too many reports.
+// suspended is false when the recruiter was already suspended or banned.
+type RecruiterSuspender interface {
+	SuspendRecruiter(ctx context.Context, recruiterID, reason string) (suspended bool, err error)
+}
+
+// Alerter raises operational alerts, such as *notifier.Notifier.
+type Alerter interface {
+	Alert(msg string, attrs ...any)
+}
+
+// reportPrefix namespaces report button data among other callbacks.
+const reportPrefix = "report:"
+
+// ReportData returns the button payload for reporting a request.
+func ReportData(reason ReportReason, requestID string) string {
+	return reportPrefix + string(reason) + ":" + requestID
+}
+
+// ParseReportData splits a button payload built by ReportData. ok is false
+// for payloads that are not reports.
+func ParseReportData(data string) (reason ReportReason, requestID string, ok bool) {
+	rest, found := strings.CutPrefix(data, reportPrefix)
+	if !found {
+		return "", "", false
+	}
+	value, requestID, found := strings.Cut(rest, ":")
+	reason = ReportReason(value)
+	if !found || requestID == "" || (reason != ReportSpam && reason != ReportAbuse) {
+		return "", "", false
+	}
+	return reason, requestID, true
+}
+
+// WithAbuseReports suspends a recruiter through suspender once their requests
+// have collected threshold reports that admins have not dismissed, and raises
+// an alert on alerts, which may be nil. threshold defaults to
+// DefaultReportThreshold when zero. Reports, reviews and suspensions are
+// written to log when it is not nil.
+func (s *Service) WithAbuseReports(threshold int, suspender RecruiterSuspender, alerts Alerter, log audit.Log) *Service {
+	if threshold <= 0 {
+		threshold = DefaultReportThreshold
+	}
+	s.reportThreshold = threshold
+	s.suspender = suspender
+	s.alerts = alerts
+	if log != nil {
+		s.audit = log
+	}
+	return s
+}
+
+// Report records a seeker's report about the recruiter of a request. A
+// request still waiting for an answer is declined without telling the
+// recruiter. Errors from the audit log or the suspension check are returned
+// with the saved entry.
+func (s *Service) Report(ctx context.Context, requestID string, reason ReportReason, details string) (LogEntry, error) {
+	if reason != ReportSpam && reason != ReportAbuse {
+		return LogEntry{}, fmt.Errorf("unknown report reason %q", reason)
+	}
+	entry, err := s.repo.Get(ctx, requestID)
+	if err != nil {
+		return LogEntry{}, fmt.Errorf("load contact request %s: %w", requestID, err)
+	}
+	if entry.Report != nil {
+		return entry, fmt.Errorf("%w: request %s", ErrAlreadyReported, requestID)
+	}
+
+	// The entry is returned unchanged when the report cannot be saved.
+	now := s.clock()
+	reported := entry
+	reported.Report = &Report{Reason: reason, Details: strings.TrimSpace(details), Status: ReportOpen, CreatedAt: now}
+	if canTransition(reported.Status, StatusDeclined) {
+		reported.Status = StatusDeclined
+	}
+	reported.UpdatedAt = now
+	if err := s.repo.Save(ctx, reported); err != nil {
+		return entry, fmt.Errorf("save contact log: %w", err)
+	}
+
+	errAudit := s.auditReport(ctx, AuditReported, seekerKey(reported.Request), reported, map[string]any{
+		"reason":    string(reason),
+		"details":   reported.Report.Details,
+		"recruiter": reported.Request.RecruiterID,
+	})
+	return reported, errors.Join(errAudit, s.checkAbuse(ctx, reported))
+}
+
+// ReviewReport records an admin's decision on a report. Dismissed reports no
+// longer count towards the suspension threshold; suspended recruiters are
+// reinstated with the admin approve command.
+func (s *Service) ReviewReport(ctx context.Context, requestID, adminID string, status ReportStatus) (LogEntry, error) {
+	if status != ReportUpheld && status != ReportDismissed {
+		return LogEntry{}, fmt.Errorf("unknown report decision %q", status)
+	}
+	entry, err := s.repo.Get(ctx, requestID)
+	if err != nil {
+		return LogEntry{}, fmt.Errorf("load contact request %s: %w", requestID, err)
+	}
+	if entry.Report == nil {
+		return entry, fmt.Errorf("%w: request %s", ErrNoReport, requestID)
+	}
+
+	now := s.clock()
+	report := *entry.Report
+	report.Status = status
+	report.ReviewedBy = adminID
+	report.ReviewedAt = &now
+	entry.Report = &report
+	entry.UpdatedAt = now
+	if err := s.repo.Save(ctx, entry); err != nil {
+		return entry, fmt.Errorf("save contact log: %w", err)
+	}
+	return entry, s.auditReport(ctx, AuditReportReviewed, adminID, entry, map[string]any{
+		"decision":  string(status),
+		"recruiter": entry.Request.RecruiterID,
+	})
+}
+
+// Reports returns the reported requests, oldest first, limited to those
+// whose report has status unless status is empty.
+func (s *Service) Reports(ctx context.Context, status ReportStatus) ([]LogEntry, error) {
+	entries, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list contact log: %w", err)
+	}
+	var reported []LogEntry
+	for _, entry := range entries {
+		if entry.Report != nil && (status == "" || entry.Report.Status == status) {
+			reported = append(reported, entry)
+		}
+	}
+	return reported, nil
+}
+
+// ReportCount returns the reports against a recruiter that were not
+// dismissed.
+func (s *Service) ReportCount(ctx context.Context, recruiterID string) (int, error) {
+	entries, err := s.repo.List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("list contact log: %w", err)
+	}
+	count := 0
+	for _, entry := range entries {
+		if entry.Request.RecruiterID == recruiterID && entry.Report != nil && entry.Report.Status != ReportDismissed {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// checkAbuse suspends the recruiter of a reported request once they reach
+// the report threshold.
+func (s *Service) checkAbuse(ctx context.Context, entry LogEntry) error {
+	recruiterID := entry.Request.RecruiterID
+	if s.suspender == nil || recruiterID == "" {
+		return nil
+	}
+	count, err := s.ReportCount(ctx, recruiterID)
+	if err != nil || count < s.reportThreshold {
+		return err
+	}
+
+	reason := fmt.Sprintf("suspended after %d spam or abuse reports", count)
+	suspended, err := s.suspender.SuspendRecruiter(ctx, recruiterID, reason)
+	if err != nil {
+		return fmt.Errorf("suspend recruiter %s: %w", recruiterID, err)
+	}
+	if !suspended {
+		return nil
+	}
+	if s.alerts != nil {
+		s.alerts.Alert("recruiter suspended after abuse reports",
+			"recruiter_id", recruiterID, "recruiter", entry.Request.RecruiterName,
+			"company", entry.Request.RecruiterCompany, "reports", count, "request_id", entry.ID)
+	}
+	return s.auditReport(ctx, AuditRecruiterSuspended, "system", entry, map[string]any{
+		"recruiter": recruiterID,
+		"reports":   count,
+	})
+}
+
+func (s *Service) auditReport(ctx context.Context, action, actorID string, entry LogEntry, metadata map[string]any) error {
+	if s.audit == nil {
+		return nil
+	}
+	err := s.audit.Record(ctx, audit.Entry{
+		ActorID:    actorID,
+		Action:     action,
+		TargetType: "contact_request",
+		TargetID:   entry.ID,
+		Metadata:   metadata,
+		CreatedAt:  s.clock(),
+	})
+	if err != nil {
+		return fmt.Errorf("audit %s of request %s: %w", action, entry.ID, err)
+	}
+	return nil
+}
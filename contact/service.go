@@ -2,9 +2,14 @@ package contact
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/Golangjobsuz/golangjobsuz/internal/audit"
 )
 
 // Notifier delivers contact requests to seekers or admin relays.
@@ -23,21 +28,75 @@ type Request struct {
 	SeekerContact    string
 	Notes            string
 	UseAdminRelay    bool
+	// RecruiterID and ProfileID identify the recruiter's user and the seeker's
+	// profile; they are needed to grant access when the seeker accepts.
+	RecruiterID string
+	ProfileID   string
+	// Salary is the optional salary range offered, e.g. "$3,000-$4,500".
+	Salary string
+	// SeekerLanguage picks the template of the seeker's message, e.g. "uz";
+	// see Service.WithTemplate.
+	SeekerLanguage string
+}
+
+// RequestStatus is the lifecycle state of a contact request.
+type RequestStatus string
+
+const (
+	// StatusRequested is a request that has not reached the seeker yet,
+	// including one whose delivery failed.
+	StatusRequested RequestStatus = "requested"
+	StatusDelivered RequestStatus = "delivered"
+	StatusAccepted  RequestStatus = "accepted"
+	StatusDeclined  RequestStatus = "declined"
+	// StatusExpired marks requests left unanswered; see ExpireStale.
+	StatusExpired RequestStatus = "expired"
+)
+
+var (
+	// ErrNotFound is returned when a contact request does not exist.
+	ErrNotFound = errors.New("contact request not found")
+	// ErrInvalidTransition is returned when a request cannot move to the
+	// requested status, e.g. accepting a request that was already declined.
+	ErrInvalidTransition = errors.New("invalid contact request transition")
+	// ErrUnreachable is returned by notifiers when the recipient cannot be
+	// messaged directly, e.g. a seeker who never started the Telegram bot.
+	// HandleRequest then falls back to the admin relay.
+	ErrUnreachable = errors.New("recipient cannot be reached directly")
+)
+
+// transitions lists the statuses a request may move to from each status.
+var transitions = map[RequestStatus][]RequestStatus{
+	StatusRequested: {StatusDelivered, StatusExpired},
+	StatusDelivered: {StatusAccepted, StatusDeclined, StatusExpired},
 }
 
-// LogEntry stores a record of notifications we attempted.
+// LogEntry stores a contact request and where it is in its lifecycle.
 type LogEntry struct {
+	// ID identifies the request so the seeker can respond to it.
+	ID        string
 	Request   Request
+	Status    RequestStatus
 	Delivered bool
 	ViaAdmin  bool
-	Timestamp time.Time
-	Error     string
+	// FallbackReason explains why a request meant for the seeker went through
+	// the admin relay instead.
+	FallbackReason string
+	Timestamp      time.Time
+	UpdatedAt      time.Time
+	Error          string
+	// AccessExpiresAt is when the recruiter's access granted on acceptance ends.
+	AccessExpiresAt *time.Time
+	// Report is the seeker's spam or abuse report, if any; see Service.Report.
+	Report *Report
 }
 
-// LogRepo persists contact request attempts.
+// LogRepo persists contact requests. Save inserts the entry or replaces the
+// one with the same ID.
 type LogRepo interface {
 	Save(ctx context.Context, entry LogEntry) error
 	List(ctx context.Context) ([]LogEntry, error)
+	Get(ctx context.Context, id string) (LogEntry, error)
 }
 
 // Service coordinates routing recruiter requests to seekers or admin relays.
@@ -45,35 +104,70 @@ type Service struct {
 	notifier Notifier
 	repo     LogRepo
 	clock    func() time.Time
+	newID    func() string
+
+	directory Directory
+	access    AccessGranter
+	accessTTL time.Duration
+
+	quota      *QuotaPolicy
+	recruiters RecruiterDirectory
+	audit      audit.Log
+	threads    ThreadRepo
+
+	templates map[string]*MessageTemplate
+
+	reportThreshold int
+	suspender       RecruiterSuspender
+	alerts          Alerter
 }
 
 // NewService constructs a contact service.
 func NewService(notifier Notifier, repo LogRepo) *Service {
 	return &Service{
-		notifier: notifier,
-		repo:     repo,
-		clock:    time.Now,
+		notifier:  notifier,
+		repo:      repo,
+		clock:     time.Now,
+		newID:     randomID,
+		accessTTL: 7 * 24 * time.Hour,
 	}
 }
 
-// HandleRequest routes the recruiter request to the seeker or admin relay.
+// HandleRequest records the recruiter request and routes it to the seeker or
+// admin relay. The request is saved as StatusRequested before it is sent and
+// moves to StatusDelivered once the notification succeeds. A seeker the
+// notifier reports as ErrUnreachable gets the request through the admin relay,
+// with the reason kept in FallbackReason. Requests refused by WithQuota return
+// a QuotaError, CooldownError or RecruiterBlockedError.
 func (s *Service) HandleRequest(ctx context.Context, req Request) (LogEntry, error) {
-	message := formatMessage(req)
-	entry := LogEntry{Request: req, Timestamp: s.clock()}
+	if err := s.checkLimits(ctx, req); err != nil {
+		return LogEntry{Request: req}, err
+	}
+
+	now := s.clock()
+	entry := LogEntry{ID: s.newID(), Request: req, Status: StatusRequested, Timestamp: now, UpdatedAt: now}
+	if err := s.repo.Save(ctx, entry); err != nil {
+		return entry, fmt.Errorf("save contact log: %w", err)
+	}
 
 	var err error
 	if req.UseAdminRelay || req.SeekerContact == "" {
-		entry.ViaAdmin = true
-		err = s.notifier.NotifyAdmin(ctx, message)
+		err = s.relayToAdmin(ctx, &entry)
 	} else {
-		err = s.notifier.NotifySeeker(ctx, req.SeekerContact, message)
+		err = s.askSeeker(ctx, entry)
+		if errors.Is(err, ErrUnreachable) {
+			entry.FallbackReason = err.Error()
+			err = s.relayToAdmin(ctx, &entry)
+		}
 	}
 
 	if err != nil {
 		entry.Error = err.Error()
 	} else {
 		entry.Delivered = true
+		entry.Status = StatusDelivered
 	}
+	entry.UpdatedAt = s.clock()
 
 	if saveErr := s.repo.Save(ctx, entry); saveErr != nil {
 		return entry, fmt.Errorf("save contact log: %w", saveErr)
@@ -86,20 +180,114 @@ func (s *Service) HandleRequest(ctx context.Context, req Request) (LogEntry, err
 	return entry, nil
 }
 
-func formatMessage(req Request) string {
-	contact := req.RecruiterContact
-	if contact == "" {
-		contact = "(contact details not provided)"
+// relayToAdmin sends the request to the admins, opening a relay thread when
+// WithRelayInbox is set.
+func (s *Service) relayToAdmin(ctx context.Context, entry *LogEntry) error {
+	entry.ViaAdmin = true
+	message := formatMessage(entry.ID, entry.Request)
+	if s.threads != nil {
+		var err error
+		if message, err = s.startThread(ctx, *entry); err != nil {
+			return err
+		}
+	}
+	if entry.FallbackReason != "" {
+		message = fmt.Sprintf("The seeker could not be reached directly (%s), so this request needs the admin relay.\n\n%s", entry.FallbackReason, message)
 	}
+	return s.notifier.NotifyAdmin(ctx, message)
+}
 
-	return fmt.Sprintf(
-		"Recruiter %s (%s) is interested in %s. Contact: %s. Notes: %s",
-		req.RecruiterName,
-		req.RecruiterCompany,
-		req.Role,
-		contact,
-		req.Notes,
-	)
+// Get returns the contact request with the given ID.
+func (s *Service) Get(ctx context.Context, id string) (LogEntry, error) {
+	return s.repo.Get(ctx, id)
+}
+
+// Accept records that the seeker agreed to be contacted and reveals their
+// contact details to the recruiter; see WithConsent.
+func (s *Service) Accept(ctx context.Context, id string) (LogEntry, error) {
+	return s.transition(ctx, id, StatusAccepted, s.reveal)
+}
+
+// Decline records that the seeker turned the request down and tells the
+// recruiter without sharing any of the seeker's details.
+func (s *Service) Decline(ctx context.Context, id string) (LogEntry, error) {
+	entry, err := s.transition(ctx, id, StatusDeclined, nil)
+	if err != nil {
+		return entry, err
+	}
+	message := fmt.Sprintf("The candidate declined your request about %s.", entry.Request.Role)
+	if err := s.notifyRecruiter(ctx, entry.Request, message); err != nil {
+		return entry, fmt.Errorf("notify recruiter: %w", err)
+	}
+	return entry, nil
+}
+
+// ExpireStale marks requests that are still unanswered after maxAge as
+// StatusExpired and returns them.
+func (s *Service) ExpireStale(ctx context.Context, maxAge time.Duration) ([]LogEntry, error) {
+	entries, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list contact log: %w", err)
+	}
+
+	cutoff := s.clock().Add(-maxAge)
+	var expired []LogEntry
+	for _, entry := range entries {
+		if entry.Status != StatusRequested && entry.Status != StatusDelivered {
+			continue
+		}
+		if entry.Timestamp.After(cutoff) {
+			continue
+		}
+		entry, err := s.transition(ctx, entry.ID, StatusExpired, nil)
+		if err != nil {
+			return expired, err
+		}
+		expired = append(expired, entry)
+	}
+	return expired, nil
+}
+
+// transition moves a request to status if its current status allows it.
+// before, when set, runs ahead of the save and aborts the change on error.
+func (s *Service) transition(ctx context.Context, id string, status RequestStatus, before func(context.Context, *LogEntry) error) (LogEntry, error) {
+	entry, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return LogEntry{}, fmt.Errorf("load contact request %s: %w", id, err)
+	}
+	if !canTransition(entry.Status, status) {
+		return entry, fmt.Errorf("%w: request %s is %s, cannot become %s", ErrInvalidTransition, id, entry.Status, status)
+	}
+	if before != nil {
+		if err := before(ctx, &entry); err != nil {
+			return entry, err
+		}
+	}
+
+	entry.Status = status
+	entry.UpdatedAt = s.clock()
+	if err := s.repo.Save(ctx, entry); err != nil {
+		return entry, fmt.Errorf("save contact log: %w", err)
+	}
+	return entry, nil
+}
+
+func canTransition(from, to RequestStatus) bool {
+	for _, allowed := range transitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// randomID returns a short random request ID that is easy to type in a reply.
+func randomID() string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
 }
 
 // MemoryLogRepo provides an in-memory LogRepo implementation for tests and simple deployments.
@@ -113,10 +301,16 @@ func NewMemoryLogRepo() *MemoryLogRepo {
 	return &MemoryLogRepo{}
 }
 
-// Save records an entry in memory.
+// Save records an entry in memory, replacing an earlier entry with the same ID.
 func (r *MemoryLogRepo) Save(_ context.Context, entry LogEntry) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
+	for i := range r.entries {
+		if entry.ID != "" && r.entries[i].ID == entry.ID {
+			r.entries[i] = entry
+			return nil
+		}
+	}
 	r.entries = append(r.entries, entry)
 	return nil
 }
@@ -129,3 +323,15 @@ func (r *MemoryLogRepo) List(_ context.Context) ([]LogEntry, error) {
 	copy(out, r.entries)
 	return out, nil
 }
+
+// Get returns the entry with the given ID or ErrNotFound.
+func (r *MemoryLogRepo) Get(_ context.Context, id string) (LogEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, entry := range r.entries {
+		if entry.ID == id {
+			return entry, nil
+		}
+	}
+	return LogEntry{}, ErrNotFound
+}
@@ -3,20 +3,30 @@ package contact
 import (
 	"context"
 	"errors"
+	"fmt"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/Golangjobsuz/golangjobsuz/internal/audit"
+	"github.com/Golangjobsuz/golangjobsuz/internal/store"
 )
 
 type mockNotifier struct {
 	seekerMessages []string
 	adminMessages  []string
 	failSeeker     bool
+	unreachable    bool
 }
 
 func (m *mockNotifier) NotifySeeker(_ context.Context, seekerContact string, message string) error {
 	if m.failSeeker {
 		return errors.New("seeker unavailable")
 	}
+	if m.unreachable {
+		return fmt.Errorf("%w: %s has not started the bot", ErrUnreachable, seekerContact)
+	}
 	m.seekerMessages = append(m.seekerMessages, seekerContact+": "+message)
 	return nil
 }
@@ -31,6 +41,12 @@ type stubLogRepo struct {
 }
 
 func (r *stubLogRepo) Save(_ context.Context, entry LogEntry) error {
+	for i := range r.entries {
+		if r.entries[i].ID == entry.ID {
+			r.entries[i] = entry
+			return nil
+		}
+	}
 	r.entries = append(r.entries, entry)
 	return nil
 }
@@ -39,6 +55,15 @@ func (r *stubLogRepo) List(_ context.Context) ([]LogEntry, error) {
 	return r.entries, nil
 }
 
+func (r *stubLogRepo) Get(_ context.Context, id string) (LogEntry, error) {
+	for _, entry := range r.entries {
+		if entry.ID == id {
+			return entry, nil
+		}
+	}
+	return LogEntry{}, ErrNotFound
+}
+
 func TestHandleRequestSendsToSeeker(t *testing.T) {
 	notifier := &mockNotifier{}
 	repo := &stubLogRepo{}
@@ -68,8 +93,11 @@ func TestHandleRequestSendsToSeeker(t *testing.T) {
 	if entry.ViaAdmin {
 		t.Fatalf("expected direct delivery, got admin relay")
 	}
-	if !entry.Delivered {
-		t.Fatalf("expected delivery flagged")
+	if !entry.Delivered || entry.Status != StatusDelivered {
+		t.Fatalf("expected delivery flagged, got %+v", entry)
+	}
+	if entry.ID == "" || !strings.Contains(notifier.seekerMessages[0], entry.ID) {
+		t.Fatalf("expected request ID in message, got %q", notifier.seekerMessages[0])
 	}
 }
 
@@ -91,8 +119,36 @@ func TestHandleRequestFallsBackToAdminRelay(t *testing.T) {
 	if len(notifier.adminMessages) != 0 {
 		t.Fatalf("admin relay should not be used when seeker contact present, even on failure")
 	}
-	if entry.Delivered {
-		t.Fatalf("entry should mark failure")
+	if entry.Delivered || entry.Status != StatusRequested {
+		t.Fatalf("entry should mark failure, got %+v", entry)
+	}
+}
+
+func TestHandleRequestRelaysUnreachableSeeker(t *testing.T) {
+	notifier := &mockNotifier{unreachable: true}
+	repo := &stubLogRepo{}
+	svc := NewService(notifier, repo).WithRelayInbox(NewMemoryThreadRepo(), nil)
+
+	req := Request{RecruiterName: "Rita", RecruiterCompany: "Talent", Role: "Backend", SeekerContact: "@sam"}
+
+	entry, err := svc.HandleRequest(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !entry.ViaAdmin || !entry.Delivered || entry.Status != StatusDelivered {
+		t.Fatalf("expected delivery through the admin relay, got %+v", entry)
+	}
+	if !strings.Contains(entry.FallbackReason, "has not started the bot") {
+		t.Fatalf("fallback reason not recorded: %q", entry.FallbackReason)
+	}
+	if saved, _ := repo.Get(context.Background(), entry.ID); saved.FallbackReason != entry.FallbackReason {
+		t.Fatalf("fallback reason not saved: %+v", saved)
+	}
+	if len(notifier.adminMessages) != 1 || !strings.Contains(notifier.adminMessages[0], "could not be reached directly") {
+		t.Fatalf("admins should be told why the request was relayed, got %v", notifier.adminMessages)
+	}
+	if _, err := svc.GetThread(context.Background(), entry.ID); err != nil {
+		t.Fatalf("expected relay thread for the request: %v", err)
 	}
 }
 
@@ -115,3 +171,554 @@ func TestHandleRequestUsesAdminWhenRequested(t *testing.T) {
 		t.Fatalf("entry should mark admin relay")
 	}
 }
+
+func TestRequestLifecycle(t *testing.T) {
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	repo := &stubLogRepo{}
+	svc := NewService(&mockNotifier{}, repo)
+	svc.clock = func() time.Time { return now }
+	ids := []string{"a1", "b2", "c3"}
+	svc.newID = func() string {
+		id := ids[0]
+		ids = ids[1:]
+		return id
+	}
+	ctx := context.Background()
+
+	for _, seeker := range []string{"@sam", "@kim", "@lee"} {
+		if _, err := svc.HandleRequest(ctx, Request{RecruiterName: "Rita", SeekerContact: seeker}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if len(repo.entries) != 3 {
+		t.Fatalf("expected one entry per request, got %d", len(repo.entries))
+	}
+
+	if entry, err := svc.Accept(ctx, "a1"); err != nil || entry.Status != StatusAccepted {
+		t.Fatalf("expected accepted, got %+v (%v)", entry, err)
+	}
+	if _, err := svc.Decline(ctx, "a1"); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("expected ErrInvalidTransition, got %v", err)
+	}
+	if entry, err := svc.Decline(ctx, "b2"); err != nil || entry.Status != StatusDeclined {
+		t.Fatalf("expected declined, got %+v (%v)", entry, err)
+	}
+	if _, err := svc.Accept(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	now = now.Add(8 * 24 * time.Hour)
+	expired, err := svc.ExpireStale(ctx, 7*24*time.Hour)
+	if err != nil || len(expired) != 1 || expired[0].ID != "c3" || expired[0].Status != StatusExpired {
+		t.Fatalf("expected only the unanswered request expired, got %+v (%v)", expired, err)
+	}
+}
+
+type consentNotifier struct {
+	mockNotifier
+	asked         []string
+	recruiters    []string
+	failRecruiter bool
+}
+
+func (c *consentNotifier) AskSeeker(_ context.Context, seekerContact, message, requestID string) error {
+	c.asked = append(c.asked, seekerContact+": "+message+" ["+ConsentData(ActionAccept, requestID)+"]")
+	return nil
+}
+
+func (c *consentNotifier) NotifyRecruiter(_ context.Context, recruiterContact, message string) error {
+	if c.failRecruiter {
+		return errors.New("recruiter unavailable")
+	}
+	c.recruiters = append(c.recruiters, recruiterContact+": "+message)
+	return nil
+}
+
+type stubAccess struct {
+	grants  []string
+	revoked []string
+	fail    bool
+}
+
+func (a *stubAccess) GrantAccess(_ context.Context, recruiterID, profileID string, expiresAt time.Time) error {
+	if a.fail {
+		return errors.New("database unavailable")
+	}
+	a.grants = append(a.grants, recruiterID+"->"+profileID+" until "+expiresAt.Format("2006-01-02"))
+	return nil
+}
+
+func (a *stubAccess) RevokeAccess(_ context.Context, recruiterID, profileID string, expiresAt time.Time) error {
+	a.revoked = append(a.revoked, recruiterID+"->"+profileID+" until "+expiresAt.Format("2006-01-02"))
+	return nil
+}
+
+type stubDirectory map[string]string
+
+func (d stubDirectory) SeekerDetails(_ context.Context, profileID string) (string, error) {
+	if details, ok := d[profileID]; ok {
+		return details, nil
+	}
+	return "", errors.New("profile not found")
+}
+
+func TestConsentRevealsDetailsOnlyOnAccept(t *testing.T) {
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	notifier := &consentNotifier{}
+	access := &stubAccess{fail: true}
+	svc := NewService(notifier, &stubLogRepo{}).WithConsent(stubDirectory{"p1": "Sam: sam@example.com"}, access, 3*24*time.Hour)
+	svc.clock = func() time.Time { return now }
+	ctx := context.Background()
+	req := Request{RecruiterName: "Rita", RecruiterContact: "@rita", RecruiterID: "42", Role: "Backend", SeekerContact: "@sam", ProfileID: "p1"}
+
+	entry, err := svc.HandleRequest(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifier.asked) != 1 || !strings.Contains(notifier.asked[0], "contact:accept:"+entry.ID) {
+		t.Fatalf("expected seeker asked with buttons, got %v", notifier.asked)
+	}
+	if len(notifier.seekerMessages) != 0 {
+		t.Fatalf("expected no plain seeker message, got %v", notifier.seekerMessages)
+	}
+
+	if _, err := svc.Accept(ctx, entry.ID); err == nil {
+		t.Fatalf("expected failed access grant to abort acceptance")
+	}
+	if got, _ := svc.Get(ctx, entry.ID); got.Status != StatusDelivered || len(notifier.recruiters) != 0 {
+		t.Fatalf("expected request left open and nothing revealed, got %+v %v", got, notifier.recruiters)
+	}
+
+	access.fail = false
+	notifier.failRecruiter = true
+	if _, err := svc.Accept(ctx, entry.ID); err == nil {
+		t.Fatalf("expected a failed reveal to abort acceptance")
+	}
+	if got, _ := svc.Get(ctx, entry.ID); got.Status != StatusDelivered || len(access.revoked) != 1 || access.revoked[0] != "42->p1 until 2024-05-04" {
+		t.Fatalf("expected the grant revoked and the request left open, got %+v %v", got, access.revoked)
+	}
+
+	notifier.failRecruiter = false
+	accepted, err := svc.Accept(ctx, entry.ID)
+	if err != nil || accepted.Status != StatusAccepted || accepted.AccessExpiresAt == nil {
+		t.Fatalf("expected accepted with access, got %+v (%v)", accepted, err)
+	}
+	if len(access.grants) != 2 || access.grants[1] != "42->p1 until 2024-05-04" {
+		t.Fatalf("unexpected grants %v", access.grants)
+	}
+	if len(notifier.recruiters) != 1 || !strings.Contains(notifier.recruiters[0], "sam@example.com") {
+		t.Fatalf("expected details revealed to the recruiter, got %v", notifier.recruiters)
+	}
+
+	declined, err := svc.HandleRequest(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.Respond(ctx, ActionDecline, declined.ID); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	last := notifier.recruiters[len(notifier.recruiters)-1]
+	if !strings.Contains(last, "declined") || strings.Contains(last, "sam") || len(access.grants) != 2 {
+		t.Fatalf("expected decline without details, got %q", last)
+	}
+}
+
+func TestSeekerMessageUsesPreferredLanguage(t *testing.T) {
+	ctx := context.Background()
+	notifier := &mockNotifier{}
+	directory := StoreDirectory{Store: &store.Store{Profiles: map[string]store.Profile{
+		"p1": {ID: "p1", Name: "Sam", Language: "uz"},
+	}}}
+	svc := NewService(notifier, &stubLogRepo{}).WithConsent(directory, nil, 0)
+
+	req := Request{RecruiterName: "Rita", RecruiterCompany: "Talent", Role: "Backend", Salary: "$2,000-$3,000", SeekerContact: "@sam", SeekerLanguage: "ru-RU"}
+	preview, err := svc.Preview(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{"Рекрутер Rita (Talent)", "Зарплата: $2,000-$3,000", "/accept PREVIEW"} {
+		if !strings.Contains(preview, want) {
+			t.Fatalf("preview %q is missing %q", preview, want)
+		}
+	}
+	if strings.Contains(preview, "Комментарий") {
+		t.Fatalf("empty notes should be left out, got %q", preview)
+	}
+	if len(notifier.seekerMessages) != 0 {
+		t.Fatalf("preview must not send anything")
+	}
+
+	req.SeekerLanguage, req.ProfileID = "", "p1"
+	entry, err := svc.HandleRequest(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := notifier.seekerMessages[0]; !strings.Contains(got, "Maosh: $2,000-$3,000") || !strings.Contains(got, "/accept "+entry.ID) {
+		t.Fatalf("expected the Uzbek template from the profile, got %q", got)
+	}
+
+	req.ProfileID = "unknown"
+	if preview, _ := svc.Preview(ctx, req); !strings.HasPrefix(preview, "Recruiter Rita (Talent)") || strings.Contains(preview, "Notes:") {
+		t.Fatalf("expected the English template without notes, got %q", preview)
+	}
+}
+
+func TestBuiltinMessageTemplatesParse(t *testing.T) {
+	for _, lang := range MessageLanguages {
+		if _, err := BuiltinMessageTemplate(lang); err != nil {
+			t.Fatalf("built-in template %s: %v", lang, err)
+		}
+	}
+	if _, err := ParseMessageTemplate("no_id", "Recruiter {{.RecruiterName}} wants to talk."); err == nil {
+		t.Fatalf("expected a template without the request ID to be rejected")
+	}
+}
+
+type stubAlerter struct {
+	alerts []string
+}
+
+func (a *stubAlerter) Alert(msg string, attrs ...any) {
+	a.alerts = append(a.alerts, fmt.Sprint(append([]any{msg}, attrs...)...))
+}
+
+func TestReportsSuspendRecruiterAtThreshold(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "store.json")
+	s, err := store.Load(path)
+	if err != nil {
+		t.Fatalf("load store: %v", err)
+	}
+	s.RecruiterAccess["42"] = store.RecruiterAccess{UserID: "42", Status: "approved", Notes: "verified company"}
+	if err := s.Save(); err != nil {
+		t.Fatalf("save store: %v", err)
+	}
+	log := audit.NewMemoryLog()
+	alerts := &stubAlerter{}
+	svc := NewService(&mockNotifier{}, &stubLogRepo{}).
+		WithQuota(QuotaPolicy{}, StoreRecruiters{Store: s}, nil).
+		WithAbuseReports(2, StoreRecruiters{Store: s}, alerts, log)
+
+	var ids []string
+	for _, seeker := range []string{"@sam", "@sara", "@sid"} {
+		entry, err := svc.HandleRequest(ctx, Request{RecruiterID: "42", RecruiterName: "Rita", Role: "Backend", SeekerContact: seeker})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		ids = append(ids, entry.ID)
+	}
+
+	reported, err := svc.Report(ctx, ids[0], ReportSpam, " bulk message ")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if reported.Status != StatusDeclined || reported.Report.Status != ReportOpen || reported.Report.Details != "bulk message" {
+		t.Fatalf("unexpected reported entry %+v %+v", reported, reported.Report)
+	}
+	if _, err := svc.Report(ctx, ids[0], ReportAbuse, ""); !errors.Is(err, ErrAlreadyReported) {
+		t.Fatalf("expected ErrAlreadyReported, got %v", err)
+	}
+	if _, err := svc.ReviewReport(ctx, ids[0], "admin", ReportDismissed); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The dismissed report does not count, so the second one stays below
+	// the threshold of two.
+	if _, err := svc.Report(ctx, ids[1], ReportAbuse, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.RecruiterAccess["42"].Status != "approved" || len(alerts.alerts) != 0 {
+		t.Fatalf("recruiter suspended too early: %+v", s.RecruiterAccess["42"])
+	}
+
+	// An admin approves another recruiter from the CLI meanwhile.
+	other, err := store.Load(path)
+	if err != nil {
+		t.Fatalf("load store: %v", err)
+	}
+	other.RecruiterAccess["7"] = store.RecruiterAccess{UserID: "7", Status: "approved"}
+	if err := other.Save(); err != nil {
+		t.Fatalf("save store: %v", err)
+	}
+
+	if _, err := svc.Report(ctx, ids[2], ReportSpam, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.RecruiterAccess["42"].Status != "suspended" || len(alerts.alerts) != 1 {
+		t.Fatalf("expected suspension and one alert, got %+v %v", s.RecruiterAccess["42"], alerts.alerts)
+	}
+	saved, err := store.Load(path)
+	if err != nil {
+		t.Fatalf("load store: %v", err)
+	}
+	if access := saved.RecruiterAccess["42"]; access.Status != "suspended" || !strings.HasPrefix(access.Notes, "verified company; ") {
+		t.Fatalf("expected suspension saved with the earlier notes, got %+v", access)
+	}
+	if saved.RecruiterAccess["7"].Status != "approved" {
+		t.Fatalf("expected the concurrent approval kept, got %+v", saved.RecruiterAccess)
+	}
+
+	var blocked *RecruiterBlockedError
+	if _, err := svc.HandleRequest(ctx, Request{RecruiterID: "42", RecruiterName: "Rita", Role: "Backend", SeekerContact: "@sue"}); !errors.As(err, &blocked) {
+		t.Fatalf("expected suspended recruiter to be blocked, got %v", err)
+	}
+	open, _ := svc.Reports(ctx, ReportOpen)
+	if len(open) != 2 {
+		t.Fatalf("expected two open reports, got %d", len(open))
+	}
+	actions := map[string]int{}
+	for _, entry := range log.Entries() {
+		actions[entry.Action]++
+	}
+	if actions[AuditReported] != 3 || actions[AuditReportReviewed] != 1 || actions[AuditRecruiterSuspended] != 1 {
+		t.Fatalf("unexpected audit actions %v", actions)
+	}
+}
+
+func TestParseConsentData(t *testing.T) {
+	action, id, ok := ParseConsentData(ConsentData(ActionDecline, "ab12"))
+	if !ok || action != ActionDecline || id != "ab12" {
+		t.Fatalf("unexpected parse %q %q %v", action, id, ok)
+	}
+	for _, data := range []string{"contact:delete:ab12", "contact:accept:", "other:accept:ab12"} {
+		if _, _, ok := ParseConsentData(data); ok {
+			t.Fatalf("expected %q rejected", data)
+		}
+	}
+	reason, id, ok := ParseReportData(ReportData(ReportAbuse, "ab12"))
+	if !ok || reason != ReportAbuse || id != "ab12" {
+		t.Fatalf("unexpected report parse %q %q %v", reason, id, ok)
+	}
+	if _, _, ok := ParseReportData("report:rude:ab12"); ok {
+		t.Fatalf("expected unknown report reason rejected")
+	}
+}
+
+type stubRecruiters map[string]string
+
+func (r stubRecruiters) RecruiterStatus(_ context.Context, recruiterID string) (string, error) {
+	return r[recruiterID], nil
+}
+
+func TestQuotaLimitsRecruiters(t *testing.T) {
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	log := audit.NewMemoryLog()
+	svc := NewService(&mockNotifier{}, &stubLogRepo{}).WithQuota(
+		QuotaPolicy{Daily: 2, Weekly: 3, Cooldown: 48 * time.Hour},
+		stubRecruiters{"42": "approved", "13": "banned"},
+		log,
+	)
+	svc.clock = func() time.Time { return now }
+	ctx := context.Background()
+	request := func(recruiter, seeker string) error {
+		_, err := svc.HandleRequest(ctx, Request{RecruiterID: recruiter, RecruiterName: "Rita", SeekerContact: seeker})
+		return err
+	}
+
+	var blocked *RecruiterBlockedError
+	if err := request("13", "@sam"); !errors.As(err, &blocked) || blocked.Status != "banned" {
+		t.Fatalf("expected banned recruiter blocked, got %v", err)
+	}
+	if err := request("", "@sam"); !errors.Is(err, ErrRecruiterRequired) {
+		t.Fatalf("expected ErrRecruiterRequired, got %v", err)
+	}
+
+	if err := request("42", "@sam"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var cooldown *CooldownError
+	if err := request("42", "@SAM"); !errors.As(err, &cooldown) || !cooldown.RetryAt.Equal(now.Add(48*time.Hour)) {
+		t.Fatalf("expected cooldown for the same seeker, got %v", err)
+	}
+	if err := request("42", "@kim"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var quota *QuotaError
+	if err := request("42", "@lee"); !errors.As(err, &quota) || quota.Period != "daily" {
+		t.Fatalf("expected daily quota, got %v", err)
+	}
+
+	now = now.Add(25 * time.Hour)
+	if err := request("42", "@lee"); err != nil {
+		t.Fatalf("expected quota reset the next day, got %v", err)
+	}
+	now = now.Add(25 * time.Hour)
+	if err := request("42", "@ann"); !errors.As(err, &quota) || quota.Period != "weekly" {
+		t.Fatalf("expected weekly quota, got %v", err)
+	}
+
+	actions := make(map[string]int)
+	for _, entry := range log.Entries() {
+		actions[entry.Action]++
+	}
+	if actions[AuditRecruiterBlocked] != 1 || actions[AuditCooldown] != 1 || actions[AuditQuotaExceeded] != 2 {
+		t.Fatalf("unexpected audit entries %v", actions)
+	}
+}
+
+func TestWithQuotaKeepsConfiguredAuditLog(t *testing.T) {
+	log := audit.NewMemoryLog()
+	svc := NewService(&mockNotifier{}, &stubLogRepo{}).
+		WithRelayInbox(NewMemoryThreadRepo(), log).
+		WithQuota(QuotaPolicy{}, stubRecruiters{"13": "banned"}, nil)
+
+	if _, err := svc.HandleRequest(context.Background(), Request{RecruiterID: "13", SeekerContact: "@sam"}); err == nil {
+		t.Fatalf("expected banned recruiter refused")
+	}
+	if entries := log.Entries(); len(entries) != 1 || entries[0].Action != AuditRecruiterBlocked {
+		t.Fatalf("expected the refusal audited in the relay log, got %+v", entries)
+	}
+}
+
+// historyLogRepo serves quota checks from ListByRecruiterSince and fails
+// List, like a log too large to scan.
+type historyLogRepo struct {
+	stubLogRepo
+	since []time.Time
+}
+
+func (r *historyLogRepo) List(context.Context) ([]LogEntry, error) {
+	return nil, errors.New("full scan")
+}
+
+func (r *historyLogRepo) ListByRecruiterSince(_ context.Context, recruiterID string, since time.Time) ([]LogEntry, error) {
+	r.since = append(r.since, since)
+	var entries []LogEntry
+	for _, entry := range r.entries {
+		if entry.Request.RecruiterID == recruiterID && entry.Timestamp.After(since) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func TestQuotaUsesRecruiterHistory(t *testing.T) {
+	now := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	repo := &historyLogRepo{}
+	svc := NewService(&mockNotifier{}, repo).WithQuota(QuotaPolicy{Daily: 1, Cooldown: 14 * 24 * time.Hour}, nil, nil)
+	svc.clock = func() time.Time { return now }
+	ctx := context.Background()
+
+	if _, err := svc.HandleRequest(ctx, Request{RecruiterID: "42", SeekerContact: "@sam"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.HandleRequest(ctx, Request{RecruiterID: "7", SeekerContact: "@kim"}); err != nil {
+		t.Fatalf("expected other recruiters unaffected, got %v", err)
+	}
+	var quota *QuotaError
+	if _, err := svc.HandleRequest(ctx, Request{RecruiterID: "42", SeekerContact: "@kim"}); !errors.As(err, &quota) {
+		t.Fatalf("expected daily quota, got %v", err)
+	}
+	if len(repo.since) != 3 || !repo.since[0].Equal(now.Add(-14*24*time.Hour)) {
+		t.Fatalf("expected history covering the cooldown, got %v", repo.since)
+	}
+}
+
+func TestForwardRelayToUnreachableSeekerStaysPending(t *testing.T) {
+	notifier := &mockNotifier{unreachable: true}
+	log := audit.NewMemoryLog()
+	svc := NewService(notifier, &stubLogRepo{}).WithRelayInbox(NewMemoryThreadRepo(), log)
+	ctx := context.Background()
+
+	entry, err := svc.HandleRequest(ctx, Request{RecruiterName: "Rita", RecruiterContact: "@rita", Role: "Backend", SeekerContact: "sam@example.com"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	thread, err := svc.GetThread(ctx, entry.ID)
+	if err != nil || thread.SeekerUnreachable == "" {
+		t.Fatalf("expected the thread to record the unreachable seeker, got %+v (%v)", thread, err)
+	}
+
+	if _, err := svc.ForwardRelay(ctx, entry.ID, "admin-1", ""); !errors.Is(err, ErrUnreachable) {
+		t.Fatalf("expected ErrUnreachable, got %v", err)
+	}
+	if thread, _ = svc.GetThread(ctx, entry.ID); thread.Pending() != 0 || thread.Messages[0].ReviewedBy != "" {
+		t.Fatalf("expected the message pending again, got %+v", thread.Messages)
+	}
+	if got := len(log.Entries()); got != 0 {
+		t.Fatalf("expected no forward audited, got %d entries", got)
+	}
+
+	notifier.unreachable = false
+	if _, err := svc.ForwardRelay(ctx, entry.ID, "admin-1", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.ForwardRelay(ctx, entry.ID, "admin-1", ""); !errors.Is(err, ErrNothingPending) {
+		t.Fatalf("expected ErrNothingPending, got %v", err)
+	}
+	if len(notifier.seekerMessages) != 1 || len(log.Entries()) != 1 {
+		t.Fatalf("expected one forward sent and audited, got %v and %d entries", notifier.seekerMessages, len(log.Entries()))
+	}
+}
+
+func TestRelayThreadForwardsReviewedMessages(t *testing.T) {
+	notifier := &consentNotifier{}
+	log := audit.NewMemoryLog()
+	threads := NewMemoryThreadRepo()
+	svc := NewService(notifier, &stubLogRepo{}).WithRelayInbox(threads, log)
+	ctx := context.Background()
+
+	entry, err := svc.HandleRequest(ctx, Request{
+		RecruiterName: "Rita", RecruiterCompany: "Talent", RecruiterContact: "@rita",
+		Role: "Backend", SeekerName: "Sam", SeekerContact: "@sam", Notes: "Remote is fine", UseAdminRelay: true,
+	})
+	if err != nil || !entry.ViaAdmin {
+		t.Fatalf("expected relayed request, got %+v (%v)", entry, err)
+	}
+	if len(notifier.adminMessages) != 1 || !strings.Contains(notifier.adminMessages[0], "/relay_forward "+entry.ID) {
+		t.Fatalf("expected admin asked to review, got %v", notifier.adminMessages)
+	}
+	if open, _ := svc.OpenThreads(ctx); len(open) != 1 || open[0].Pending() != 0 {
+		t.Fatalf("expected one open thread with a pending message, got %+v", open)
+	}
+
+	if _, err := svc.ForwardRelay(ctx, entry.ID, "admin-1", "A recruiter would like to discuss a Backend role."); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifier.seekerMessages) != 1 {
+		t.Fatalf("expected edited intro forwarded to the seeker, got %v", notifier.seekerMessages)
+	}
+	forwarded := notifier.seekerMessages[0]
+	if !strings.Contains(forwarded, "discuss a Backend role") || strings.Contains(forwarded, "Rita") || strings.Contains(forwarded, "Remote is fine") {
+		t.Fatalf("expected only the edited text without identity, got %q", forwarded)
+	}
+	if _, err := svc.ForwardRelay(ctx, entry.ID, "admin-1", ""); !errors.Is(err, ErrNothingPending) {
+		t.Fatalf("expected ErrNothingPending, got %v", err)
+	}
+
+	if _, err := svc.ReplyInThread(ctx, entry.ID, PartySeeker, "Call me at +998901234567"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.RejectRelay(ctx, entry.ID, "admin-1", "no phone numbers"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifier.recruiters) != 0 {
+		t.Fatalf("expected rejected reply kept from the recruiter, got %v", notifier.recruiters)
+	}
+	if last := notifier.seekerMessages[len(notifier.seekerMessages)-1]; !strings.Contains(last, "no phone numbers") {
+		t.Fatalf("expected seeker told about the rejection, got %q", last)
+	}
+
+	if _, err := svc.ReplyInThread(ctx, entry.ID, PartySeeker, "Happy to talk on Friday"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.ForwardRelay(ctx, entry.ID, "admin-1", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(notifier.recruiters) != 1 || !strings.Contains(notifier.recruiters[0], "Happy to talk on Friday") || strings.Contains(notifier.recruiters[0], "Sam") {
+		t.Fatalf("expected anonymous reply relayed to the recruiter, got %v", notifier.recruiters)
+	}
+
+	if _, err := svc.CloseRelay(ctx, entry.ID, "admin-1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.ReplyInThread(ctx, entry.ID, PartyRecruiter, "Hello?"); !errors.Is(err, ErrThreadClosed) {
+		t.Fatalf("expected ErrThreadClosed, got %v", err)
+	}
+	if open, _ := svc.OpenThreads(ctx); len(open) != 0 {
+		t.Fatalf("expected no open threads, got %d", len(open))
+	}
+	if got := len(log.Entries()); got != 4 {
+		t.Fatalf("expected every review decision audited, got %d entries", got)
+	}
+}
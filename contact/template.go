@@ -0,0 +1,222 @@
+package contact
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+//go:embed templates/*.tmpl
+var builtinTemplates embed.FS
+
+// MessageLanguages lists the languages of the built-in request templates.
+var MessageLanguages = []string{"en", "ru", "uz"}
+
+// DefaultLanguage is used for seekers whose language is unknown or has no
+// template, and for the messages sent to admins.
+const DefaultLanguage = "en"
+
+// previewID stands in for the request ID in previews, which are rendered
+// before the request is saved.
+const previewID = "PREVIEW"
+
+// MessageData is the value request templates are executed with.
+type MessageData struct {
+	RequestID        string
+	RecruiterName    string
+	Company          string
+	RecruiterContact string
+	Role             string
+	Salary           string
+	Notes            string
+	// ReplyCommands is set when the seeker has to answer with /accept <id>,
+	// /decline <id> or /report <id> because the notifier cannot show buttons.
+	ReplyCommands bool
+}
+
+// MessageTemplate renders the message a seeker receives for a contact
+// request from a text/template.
+type MessageTemplate struct {
+	name string
+	tmpl *template.Template
+}
+
+// defaultMessage is the English built-in template, also used for admins.
+var defaultMessage = mustBuiltinMessageTemplate(DefaultLanguage)
+
+// ParseMessageTemplate parses and validates a request template. The template
+// is rendered against a complete and an empty sample request and must show
+// the request ID in both, since seekers need it to answer.
+func ParseMessageTemplate(name, text string) (*MessageTemplate, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parse message template %s: %w", name, err)
+	}
+	message := &MessageTemplate{name: name, tmpl: tmpl}
+
+	samples := []Request{
+		{
+			RecruiterName:    "Rita",
+			RecruiterCompany: "Example Inc.",
+			RecruiterContact: "@example_hr",
+			Role:             "Senior Go Developer",
+			Salary:           "$3,000-$4,500",
+			Notes:            "Remote is fine.",
+		},
+		{},
+	}
+	for _, sample := range samples {
+		out, err := message.Render("a1b2c3", sample, true)
+		if err != nil {
+			return nil, fmt.Errorf("validate message template %s: %w", name, err)
+		}
+		if !strings.Contains(out, "a1b2c3") {
+			return nil, fmt.Errorf("validate message template %s: the request ID is missing", name)
+		}
+	}
+	return message, nil
+}
+
+// LoadMessageTemplate reads and validates a request template file. The
+// template is named after the file without its extension.
+func LoadMessageTemplate(path string) (*MessageTemplate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read message template: %w", err)
+	}
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	return ParseMessageTemplate(name, string(data))
+}
+
+// BuiltinMessageTemplate returns the built-in template for one of
+// MessageLanguages.
+func BuiltinMessageTemplate(lang string) (*MessageTemplate, error) {
+	data, err := builtinTemplates.ReadFile("templates/request_" + lang + ".tmpl")
+	if err != nil {
+		return nil, fmt.Errorf("no built-in message template for language %q", lang)
+	}
+	return ParseMessageTemplate("request_"+lang, string(data))
+}
+
+func mustBuiltinMessageTemplate(lang string) *MessageTemplate {
+	message, err := BuiltinMessageTemplate(lang)
+	if err != nil {
+		panic(err)
+	}
+	return message
+}
+
+// Name returns the template name.
+func (m *MessageTemplate) Name() string {
+	return m.name
+}
+
+// Render executes the template for a request.
+func (m *MessageTemplate) Render(id string, req Request, replyCommands bool) (string, error) {
+	var b strings.Builder
+	err := m.tmpl.Execute(&b, MessageData{
+		RequestID:        id,
+		RecruiterName:    strings.TrimSpace(req.RecruiterName),
+		Company:          strings.TrimSpace(req.RecruiterCompany),
+		RecruiterContact: strings.TrimSpace(req.RecruiterContact),
+		Role:             strings.TrimSpace(req.Role),
+		Salary:           strings.TrimSpace(req.Salary),
+		Notes:            strings.TrimSpace(req.Notes),
+		ReplyCommands:    replyCommands,
+	})
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(b.String()), nil
+}
+
+// LanguageDirectory is implemented by directories that know a seeker's
+// preferred language, such as StoreDirectory.
+type LanguageDirectory interface {
+	SeekerLanguage(ctx context.Context, profileID string) (string, error)
+}
+
+// WithTemplate replaces the request template used for seekers who prefer
+// lang, or adds one for a new language.
+func (s *Service) WithTemplate(lang string, tmpl *MessageTemplate) *Service {
+	if s.templates == nil {
+		s.templates = make(map[string]*MessageTemplate)
+	}
+	s.templates[normalizeLanguage(lang)] = tmpl
+	return s
+}
+
+// Preview returns the message the seeker of req would receive, in their
+// language, without saving or sending anything. The request ID is shown as
+// PREVIEW since it is assigned when the request is sent.
+func (s *Service) Preview(ctx context.Context, req Request) (string, error) {
+	return s.seekerMessage(ctx, previewID, req)
+}
+
+// seekerMessage renders a request in the seeker's language, listing the
+// reply commands when the notifier has no buttons.
+func (s *Service) seekerMessage(ctx context.Context, id string, req Request) (string, error) {
+	_, buttons := s.notifier.(ConsentNotifier)
+	tmpl := s.template(s.seekerLanguage(ctx, req))
+	message, err := tmpl.Render(id, req, !buttons)
+	if err != nil && tmpl != defaultMessage {
+		return defaultMessage.Render(id, req, !buttons)
+	}
+	return message, err
+}
+
+// seekerLanguage picks the request's SeekerLanguage, else the language the
+// directory has for the seeker's profile.
+func (s *Service) seekerLanguage(ctx context.Context, req Request) string {
+	if req.SeekerLanguage != "" {
+		return normalizeLanguage(req.SeekerLanguage)
+	}
+	if languages, ok := s.directory.(LanguageDirectory); ok && req.ProfileID != "" {
+		if lang, err := languages.SeekerLanguage(ctx, req.ProfileID); err == nil && lang != "" {
+			return normalizeLanguage(lang)
+		}
+	}
+	return DefaultLanguage
+}
+
+func (s *Service) template(lang string) *MessageTemplate {
+	if tmpl, ok := s.templates[lang]; ok {
+		return tmpl
+	}
+	if tmpl, ok := builtinMessages[lang]; ok {
+		return tmpl
+	}
+	return defaultMessage
+}
+
+// builtinMessages holds the built-in template of each of MessageLanguages.
+var builtinMessages = func() map[string]*MessageTemplate {
+	templates := make(map[string]*MessageTemplate, len(MessageLanguages))
+	for _, lang := range MessageLanguages {
+		templates[lang] = mustBuiltinMessageTemplate(lang)
+	}
+	return templates
+}()
+
+// normalizeLanguage reduces a language tag such as Telegram's "ru-RU" to its
+// primary subtag.
+func normalizeLanguage(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if i := strings.IndexAny(lang, "-_"); i >= 0 {
+		lang = lang[:i]
+	}
+	return lang
+}
+
+// formatMessage renders a request with the default template for the admins.
+func formatMessage(id string, req Request) string {
+	message, err := defaultMessage.Render(id, req, false)
+	if err != nil {
+		return fmt.Sprintf("Contact request %s from %s about %s.", id, req.RecruiterName, req.Role)
+	}
+	return message
+}
@@ -0,0 +1,88 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Entry describes one administrative action.
+type Entry struct {
+	// ActorID is the platform user ID (e.g. Telegram ID) of the admin.
+	ActorID    string
+	Action     string
+	TargetType string
+	TargetID   string
+	Metadata   map[string]any
+	CreatedAt  time.Time
+}
+
+// Log records administrative actions.
+type Log interface {
+	Record(ctx context.Context, entry Entry) error
+}
+
+// PostgresLog writes entries to the audit_logs table.
+type PostgresLog struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresLog constructs an audit log backed by the given pool.
+func NewPostgresLog(pool *pgxpool.Pool) *PostgresLog {
+	return &PostgresLog{pool: pool}
+}
+
+// Record inserts the entry. The actor is linked to users.id through its
+// platform user ID when the user is known; the raw ID is always kept in the
+// metadata as actor_id.
+func (l *PostgresLog) Record(ctx context.Context, entry Entry) error {
+	metadata := make(map[string]any, len(entry.Metadata)+1)
+	for k, v := range entry.Metadata {
+		metadata[k] = v
+	}
+	metadata["actor_id"] = entry.ActorID
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now().UTC()
+	}
+
+	_, err := l.pool.Exec(ctx, `
+INSERT INTO audit_logs (user_id, action, target_type, target_id, metadata, created_at)
+VALUES ((SELECT id FROM users WHERE platform_user_id = $1), $2, $3, $4, $5, $6)`,
+		entry.ActorID, entry.Action, entry.TargetType, entry.TargetID, metadata, entry.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("record audit %s: %w", entry.Action, err)
+	}
+	return nil
+}
+
+// MemoryLog keeps entries in memory for tests and local runs.
+type MemoryLog struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// NewMemoryLog constructs an empty in-memory audit log.
+func NewMemoryLog() *MemoryLog {
+	return &MemoryLog{}
+}
+
+// Record appends the entry.
+func (l *MemoryLog) Record(_ context.Context, entry Entry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now().UTC()
+	}
+	l.entries = append(l.entries, entry)
+	return nil
+}
+
+// Entries returns a copy of the recorded entries.
+func (l *MemoryLog) Entries() []Entry {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Entry(nil), l.entries...)
+}
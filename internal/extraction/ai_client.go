@@ -5,7 +5,16 @@ import (
 )
 
 // AIClient defines the behavior for any provider that can extract structured candidate
-// profiles from unstructured text using an LLM.
+// profiles from unstructured text using an LLM. When Extract fails after
+// calling the provider, the returned Draft still carries the Cost of the calls.
 type AIClient interface {
 	Extract(ctx context.Context, sourceText string) (Draft, error)
 }
+
+// AIClientFunc adapts a function to the AIClient interface.
+type AIClientFunc func(ctx context.Context, sourceText string) (Draft, error)
+
+// Extract calls f.
+func (f AIClientFunc) Extract(ctx context.Context, sourceText string) (Draft, error) {
+	return f(ctx, sourceText)
+}
@@ -0,0 +1,335 @@
+package extraction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrBudgetExceeded is returned by BudgetGuard when a spend limit is reached.
+var ErrBudgetExceeded = errors.New("AI budget exceeded")
+
+// BudgetAlertRatio is the share of a limit at which admins are alerted.
+const BudgetAlertRatio = 0.8
+
+// BudgetLimits are estimated spend limits in USD per UTC day and month, for
+// the whole system and for each user. Zero means no limit.
+type BudgetLimits struct {
+	Daily       float64
+	Monthly     float64
+	UserDaily   float64
+	UserMonthly float64
+}
+
+// Alerter raises operational alerts to admins, such as *notifier.Notifier.
+type Alerter interface {
+	Alert(msg string, attrs ...any)
+}
+
+type userKey struct{}
+
+// WithUser tags ctx with the user an extraction is made for, so BudgetGuard
+// can apply the per-user limits.
+func WithUser(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userKey{}, userID)
+}
+
+// UserFromContext returns the user set by WithUser, or "".
+func UserFromContext(ctx context.Context) string {
+	userID, _ := ctx.Value(userKey{}).(string)
+	return userID
+}
+
+// SpendStore keeps the spend recorded by a BudgetGuard across restarts.
+type SpendStore interface {
+	RecordSpend(ctx context.Context, userID string, cost float64, at time.Time) error
+	// SpendSince returns the spend recorded since a time per user, with
+	// spend made without a user under "".
+	SpendSince(ctx context.Context, since time.Time) (map[string]float64, error)
+}
+
+// BudgetGuard is an AIClient that stops extractions once the estimated spend
+// of the day or month reaches a limit. Spend is taken from Draft.Cost, failed
+// extractions included, and kept in memory; WithStore keeps it across
+// restarts. While an extraction runs, the average cost of an extraction is
+// reserved against the limits, so concurrent extractions cannot all slip
+// under a nearly used limit. Extractions without a user only count towards
+// the system limits.
+type BudgetGuard struct {
+	client AIClient
+	limits BudgetLimits
+	alerts Alerter
+	queue  bool
+	store  SpendStore
+
+	mu      sync.Mutex
+	day     string
+	month   string
+	daily   map[string]float64
+	monthly map[string]float64
+	pending map[string]float64
+	alerted map[string]bool
+	// reservation is reserved per extraction until costs have been seen;
+	// afterwards their average is.
+	reservation float64
+	total       float64
+	calls       int
+	clock       func() time.Time
+	sleep       func(ctx context.Context, d time.Duration) error
+}
+
+// NewBudgetGuard wraps client with limits. Admins are alerted on alerts, which
+// may be nil, the first time a limit is crossed by BudgetAlertRatio and when
+// it is reached.
+func NewBudgetGuard(client AIClient, limits BudgetLimits, alerts Alerter) *BudgetGuard {
+	return &BudgetGuard{
+		client:  client,
+		limits:  limits,
+		alerts:  alerts,
+		daily:   make(map[string]float64),
+		monthly: make(map[string]float64),
+		pending: make(map[string]float64),
+		alerted: make(map[string]bool),
+		clock:   time.Now,
+		sleep:   sleepContext,
+	}
+}
+
+// WithQueue makes extractions over a limit wait until the limit resets, or
+// their context is done, instead of failing with ErrBudgetExceeded.
+func (g *BudgetGuard) WithQueue() *BudgetGuard {
+	g.queue = true
+	return g
+}
+
+// WithReservation sets the cost reserved for an extraction in flight before
+// the guard has seen what extractions cost.
+func (g *BudgetGuard) WithReservation(cost float64) *BudgetGuard {
+	g.reservation = cost
+	return g
+}
+
+// WithStore saves every recorded cost to store. Call LoadSpend to start from
+// the spend already saved for the current day and month.
+func (g *BudgetGuard) WithStore(store SpendStore) *BudgetGuard {
+	g.store = store
+	return g
+}
+
+// LoadSpend replaces the spend of the current day and month with the spend
+// saved in the store set by WithStore.
+func (g *BudgetGuard) LoadSpend(ctx context.Context) error {
+	if g.store == nil {
+		return errors.New("budget guard has no spend store")
+	}
+	now := g.clock().UTC()
+	monthly, err := g.store.SpendSince(ctx, time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		return fmt.Errorf("load monthly AI spend: %w", err)
+	}
+	daily, err := g.store.SpendSince(ctx, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		return fmt.Errorf("load daily AI spend: %w", err)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.rollover()
+	g.monthly, g.daily = spendTotals(monthly), spendTotals(daily)
+	return nil
+}
+
+// spendTotals turns the per-user spend of a SpendStore into guard totals,
+// where "" holds the system spend.
+func spendTotals(byUser map[string]float64) map[string]float64 {
+	totals := make(map[string]float64, len(byUser)+1)
+	for userID, cost := range byUser {
+		if userID != "" {
+			totals[userID] += cost
+		}
+		totals[""] += cost
+	}
+	return totals
+}
+
+// Extract implements AIClient. The cost of a failed extraction is counted
+// too, and the draft returned with the error carries it.
+func (g *BudgetGuard) Extract(ctx context.Context, sourceText string) (Draft, error) {
+	userID := UserFromContext(ctx)
+	var reserved float64
+	for {
+		wait, amount, err := g.reserve(userID)
+		if err == nil {
+			reserved = amount
+			break
+		}
+		if !g.queue {
+			return Draft{}, err
+		}
+		if err := g.sleep(ctx, wait); err != nil {
+			return Draft{}, err
+		}
+	}
+
+	draft, err := g.client.Extract(ctx, sourceText)
+	g.record(ctx, userID, reserved, draft.Cost)
+	return draft, err
+}
+
+// Spent returns the estimated spend of the current day and month for a
+// user, or for the whole system when userID is "". Reservations of
+// extractions in flight are not included.
+func (g *BudgetGuard) Spent(userID string) (daily, monthly float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.rollover()
+	return g.daily[userID], g.monthly[userID]
+}
+
+// budgetScope is one limit checked by BudgetGuard.
+type budgetScope struct {
+	period string
+	user   string
+	limit  float64
+	spent  float64
+	resets time.Time
+}
+
+func (s budgetScope) String() string {
+	if s.user == "" {
+		return "system " + s.period
+	}
+	return s.period + " for user " + s.user
+}
+
+// scopes lists the limits that apply to userID. g.mu must be held.
+func (g *BudgetGuard) scopes(userID string) []budgetScope {
+	now := g.clock().UTC()
+	tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+	nextMonth := time.Date(now.Year(), now.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+
+	scopes := []budgetScope{
+		{period: "daily", limit: g.limits.Daily, spent: g.daily[""], resets: tomorrow},
+		{period: "monthly", limit: g.limits.Monthly, spent: g.monthly[""], resets: nextMonth},
+	}
+	if userID != "" {
+		scopes = append(scopes,
+			budgetScope{period: "daily", user: userID, limit: g.limits.UserDaily, spent: g.daily[userID], resets: tomorrow},
+			budgetScope{period: "monthly", user: userID, limit: g.limits.UserMonthly, spent: g.monthly[userID], resets: nextMonth},
+		)
+	}
+	return scopes
+}
+
+// reserve returns ErrBudgetExceeded and the time until the limit resets when
+// an extraction for userID is over a limit, counting the reservations of
+// extractions in flight. Otherwise it reserves the estimated cost of the
+// extraction and returns it.
+func (g *BudgetGuard) reserve(userID string) (time.Duration, float64, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.rollover()
+	for _, scope := range g.scopes(userID) {
+		if scope.limit > 0 && scope.spent+g.pending[scope.user] >= scope.limit {
+			return scope.resets.Sub(g.clock()), 0, fmt.Errorf("%w: %s limit of $%.2f reached", ErrBudgetExceeded, scope, scope.limit)
+		}
+	}
+
+	estimate := g.reservation
+	if g.calls > 0 {
+		estimate = g.total / float64(g.calls)
+	}
+	g.pending[""] += estimate
+	if userID != "" {
+		g.pending[userID] += estimate
+	}
+	return 0, estimate, nil
+}
+
+// record releases the reservation of an extraction and adds its cost to the
+// spend of userID and the system, alerting admins about limits that cross
+// BudgetAlertRatio or are reached. The cost is saved to the store, if any;
+// admins are alerted when that fails.
+func (g *BudgetGuard) record(ctx context.Context, userID string, reserved, cost float64) {
+	now := g.clock()
+	g.mu.Lock()
+	g.rollover()
+	g.release("", reserved)
+	g.daily[""] += cost
+	g.monthly[""] += cost
+	if userID != "" {
+		g.release(userID, reserved)
+		g.daily[userID] += cost
+		g.monthly[userID] += cost
+	}
+	if cost > 0 {
+		g.total += cost
+		g.calls++
+	}
+	if g.alerts != nil {
+		g.alert(userID)
+	}
+	g.mu.Unlock()
+
+	if g.store == nil || cost <= 0 {
+		return
+	}
+	// The spend was made even if the caller has given up on the extraction.
+	if err := g.store.RecordSpend(context.WithoutCancel(ctx), userID, cost, now); err != nil && g.alerts != nil {
+		g.alerts.Alert("AI spend not saved", "user", userID, "cost_usd", cost, "error", err.Error())
+	}
+}
+
+// release drops a reservation of userID, forgetting reservations that are
+// used up. g.mu must be held.
+func (g *BudgetGuard) release(userID string, reserved float64) {
+	if g.pending[userID] -= reserved; g.pending[userID] < 1e-9 {
+		delete(g.pending, userID)
+	}
+}
+
+// alert raises the alerts of the limits for userID that have been crossed
+// for the first time. g.mu must be held.
+func (g *BudgetGuard) alert(userID string) {
+	for _, scope := range g.scopes(userID) {
+		if scope.limit <= 0 {
+			continue
+		}
+		for _, level := range []float64{BudgetAlertRatio, 1} {
+			key := fmt.Sprintf("%s/%s/%.2f", scope.period, scope.user, level)
+			if scope.spent < scope.limit*level || g.alerted[key] {
+				continue
+			}
+			g.alerted[key] = true
+			g.alerts.Alert(fmt.Sprintf("AI budget %.0f%% used", level*100),
+				"scope", scope.String(), "spent_usd", scope.spent, "limit_usd", scope.limit)
+		}
+	}
+}
+
+// rollover starts a new day or month of spend. g.mu must be held.
+func (g *BudgetGuard) rollover() {
+	now := g.clock().UTC()
+	day, month := now.Format("2006-01-02"), now.Format("2006-01")
+	if month != g.month {
+		g.month = month
+		g.monthly = make(map[string]float64)
+		g.resetAlerts("monthly/")
+	}
+	if day != g.day {
+		g.day = day
+		g.daily = make(map[string]float64)
+		g.resetAlerts("daily/")
+	}
+}
+
+func (g *BudgetGuard) resetAlerts(period string) {
+	for key := range g.alerted {
+		if strings.HasPrefix(key, period) {
+			delete(g.alerted, key)
+		}
+	}
+}
@@ -10,3 +10,23 @@ var DefaultOpenAICosts = map[string]TokenCost{
 
 // DefaultGeminiCost provides an optional fallback cost configuration.
 var DefaultGeminiCost = TokenCost{InputCost: 0.00035, OutputCost: 0.00105}
+
+// Estimate returns the cost of a call with the given token counts.
+func (c TokenCost) Estimate(promptTokens, completionTokens int) float64 {
+	return float64(promptTokens)/1000.0*c.InputCost + float64(completionTokens)/1000.0*c.OutputCost
+}
+
+// HasPrice reports whether calls to model of provider are priced by
+// NewProvider. Unpriced calls cost nothing, so budgets cannot be enforced
+// for them.
+func HasPrice(provider, model string) bool {
+	switch provider {
+	case "openai":
+		_, ok := DefaultOpenAICosts[model]
+		return ok
+	case "gemini":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,432 @@
+package extraction
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+	"google.golang.org/api/googleapi"
+)
+
+type stubClient struct {
+	drafts []Draft
+	errs   []error
+	calls  int
+}
+
+func (s *stubClient) Extract(ctx context.Context, _ string) (Draft, error) {
+	i := s.calls
+	s.calls++
+	if i < len(s.errs) && s.errs[i] != nil {
+		return Draft{}, s.errs[i]
+	}
+	if i < len(s.drafts) {
+		return s.drafts[i], nil
+	}
+	return Draft{Profile: CandidateProfile{Name: "Sam", Skills: []string{"go"}}, Model: "stub"}, nil
+}
+
+func quietLogger() *log.Logger {
+	return log.New(io.Discard, "", 0)
+}
+
+func TestFallbackClientSkipsFailingProviders(t *testing.T) {
+	primary := &stubClient{errs: []error{errors.New("503 service unavailable")}}
+	invalid := &stubClient{drafts: []Draft{{Profile: CandidateProfile{Name: "Sam"}, RawResponse: `{"name":"Sam"}`}}}
+	backup := &stubClient{}
+	client := NewFallbackClient(quietLogger(),
+		Provider{Name: "openai", Client: primary},
+		Provider{Name: "broken", Client: invalid},
+		Provider{Name: "gemini", Client: backup},
+	)
+
+	draft, err := client.Extract(context.Background(), "resume")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if draft.Provider != "gemini" {
+		t.Fatalf("expected gemini to answer, got %q", draft.Provider)
+	}
+	if len(draft.Skipped) != 2 || draft.Skipped[0].Reason != SkipError || draft.Skipped[1].Reason != SkipValidation {
+		t.Fatalf("unexpected skips %+v", draft.Skipped)
+	}
+}
+
+func TestFallbackClientReportsTimeouts(t *testing.T) {
+	slow := AIClientFunc(func(ctx context.Context, _ string) (Draft, error) {
+		<-ctx.Done()
+		return Draft{}, ctx.Err()
+	})
+	client := NewFallbackClient(quietLogger(),
+		Provider{Name: "slow", Client: slow, Timeout: 10 * time.Millisecond},
+		Provider{Name: "gemini", Client: &stubClient{}},
+	)
+
+	draft, err := client.Extract(context.Background(), "resume")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(draft.Skipped) != 1 || draft.Skipped[0].Reason != SkipTimeout {
+		t.Fatalf("expected a timeout skip, got %+v", draft.Skipped)
+	}
+}
+
+func TestCircuitBreakerOpensAndRecovers(t *testing.T) {
+	failing := &stubClient{errs: []error{errors.New("boom"), errors.New("boom"), nil, nil}}
+	client := NewFallbackClient(quietLogger(), Provider{Name: "openai", Client: failing}).WithBreaker(2, time.Minute)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	client.Breaker("openai").clock = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Extract(context.Background(), "resume"); err == nil {
+			t.Fatalf("expected attempt %d to fail", i+1)
+		}
+	}
+	if state := client.Breaker("openai").State(); state != BreakerOpen {
+		t.Fatalf("expected open breaker, got %s", state)
+	}
+	if _, err := client.Extract(context.Background(), "resume"); !errors.Is(err, ErrCircuitOpen) || failing.calls != 2 {
+		t.Fatalf("expected the open breaker to skip the provider, got %v after %d calls", err, failing.calls)
+	}
+
+	now = now.Add(time.Minute)
+	draft, err := client.Extract(context.Background(), "resume")
+	if err != nil || draft.Provider != "openai" {
+		t.Fatalf("expected the trial call to succeed, got %v", err)
+	}
+	if state := client.Breaker("openai").State(); state != BreakerClosed {
+		t.Fatalf("expected closed breaker, got %s", state)
+	}
+}
+
+func TestClassifyError(t *testing.T) {
+	cases := []struct {
+		err  error
+		want ErrorClass
+	}{
+		{&openai.APIError{HTTPStatusCode: 429, Message: "Rate limit reached. Please try again in 20s."}, ErrorRateLimited},
+		{&openai.APIError{HTTPStatusCode: 401, Message: "Incorrect API key provided"}, ErrorAuth},
+		{&openai.RequestError{HTTPStatusCode: 400, Err: errors.New("bad request")}, ErrorInvalidRequest},
+		{fmt.Errorf("wrapped: %w", &googleapi.Error{Code: 503}), ErrorServer},
+		{errors.New("rpc error: code = PermissionDenied desc = API key not valid"), ErrorAuth},
+		{errors.New("rpc error: code = ResourceExhausted desc = quota exceeded"), ErrorRateLimited},
+		{errors.New("connection reset by peer"), ErrorRetryable},
+	}
+	for _, tc := range cases {
+		if got := ClassifyError(tc.err); got != tc.want {
+			t.Errorf("ClassifyError(%v) = %s, want %s", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestRetryStopsOnPermanentErrors(t *testing.T) {
+	retry := DefaultRetry(3)
+	retry.sleep = func(context.Context, time.Duration) error { return nil }
+
+	attempts, err := retry.Do(context.Background(), quietLogger(), "openai", func(context.Context, int) error {
+		return &openai.APIError{HTTPStatusCode: 401, Message: "Incorrect API key provided"}
+	})
+	if err == nil || attempts != 1 {
+		t.Fatalf("expected a single failed attempt, got %d: %v", attempts, err)
+	}
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	var waits []time.Duration
+	retry := DefaultRetry(3)
+	retry.sleep = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return nil
+	}
+
+	limited := &googleapi.Error{Code: 429, Header: http.Header{"Retry-After": []string{"7"}}}
+	attempts, err := retry.Do(context.Background(), quietLogger(), "gemini", func(_ context.Context, attempt int) error {
+		switch attempt {
+		case 1:
+			return limited
+		case 2:
+			return errors.New("503 service unavailable")
+		default:
+			return nil
+		}
+	})
+	if err != nil || attempts != 3 {
+		t.Fatalf("expected success on the third attempt, got %d: %v", attempts, err)
+	}
+	if len(waits) != 2 || waits[0] != 7*time.Second || waits[1] != 500*time.Millisecond {
+		t.Fatalf("unexpected waits %v", waits)
+	}
+	if wait, ok := retryAfter(errors.New("Rate limit reached. Please try again in 120ms.")); !ok || wait != 120*time.Millisecond {
+		t.Fatalf("expected 120ms from the message, got %v", wait)
+	}
+}
+
+func TestRetryStopsWhenContextIsDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	retry := DefaultRetry(5)
+	retry.Base = time.Hour
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	attempts, err := retry.Do(ctx, quietLogger(), "openai", func(context.Context, int) error {
+		calls++
+		return errors.New("503 service unavailable")
+	})
+	if !errors.Is(err, context.Canceled) || attempts != 1 || calls != 1 {
+		t.Fatalf("expected the wait to end with the context, got %d attempts: %v", attempts, err)
+	}
+}
+
+func TestOpenAIClientRepairsInvalidJSON(t *testing.T) {
+	answers := []string{`{"name":"Sam"}`, `{"name":"Sam","skills":["go"],"experience_years":4}`}
+	var requests []openai.ChatCompletionRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req openai.ChatCompletionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		requests = append(requests, req)
+		answer := answers[len(requests)-1]
+		_ = json.NewEncoder(w).Encode(openai.ChatCompletionResponse{
+			Model:   "gpt-4o-mini",
+			Usage:   openai.Usage{PromptTokens: 1000, CompletionTokens: 1000},
+			Choices: []openai.ChatCompletionChoice{{Message: openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: answer}}},
+		})
+	}))
+	defer server.Close()
+
+	config := openai.DefaultConfig("test")
+	config.BaseURL = server.URL + "/v1"
+	client := NewOpenAIClient("test", "gpt-4o-mini", 0, 0, quietLogger(), nil, DefaultOpenAICosts)
+	client.client = openai.NewClientWithConfig(config)
+	observer := &recordingObserver{}
+	client.WithObserver(observer)
+	client.retry.sleep = func(context.Context, time.Duration) error { return nil }
+
+	draft, err := client.Extract(context.Background(), "Sam, Go developer")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if draft.Attempts != 2 || len(draft.Rejected) != 1 || draft.Rejected[0] != answers[0] {
+		t.Fatalf("expected one rejected response, got %d attempts and %q", draft.Attempts, draft.Rejected)
+	}
+
+	repair := requests[1].Messages
+	if len(repair) != 4 || repair[2].Content != answers[0] || !strings.Contains(repair[3].Content, "Skills") {
+		t.Fatalf("expected a repair request quoting the rejected JSON and errors, got %+v", repair)
+	}
+	if observer.calls != 2 || observer.retries != 1 || observer.invalid != 1 || math.Abs(observer.cost-0.0015) > 1e-9 || math.Abs(draft.Cost-0.0015) > 1e-9 {
+		t.Fatalf("unexpected observed usage %+v", observer)
+	}
+}
+
+type recordingObserver struct {
+	calls, retries, invalid int
+	cost                    float64
+}
+
+func (o *recordingObserver) ObserveCall(_, _ string, _ time.Duration, _, _ int, cost float64) {
+	o.calls++
+	o.cost += cost
+}
+
+func (o *recordingObserver) ObserveRetry(_, _, _ string) { o.retries++ }
+
+func (o *recordingObserver) ObserveValidationFailure(_, _ string) { o.invalid++ }
+
+type recordingAlerter struct{ alerts []string }
+
+func (a *recordingAlerter) Alert(msg string, attrs ...any) {
+	a.alerts = append(a.alerts, fmt.Sprint(append([]any{msg}, attrs...)...))
+}
+
+func TestBudgetGuardEnforcesUserAndSystemLimits(t *testing.T) {
+	client := AIClientFunc(func(context.Context, string) (Draft, error) {
+		return Draft{Profile: CandidateProfile{Name: "Sam", Skills: []string{"go"}}, Cost: 1}, nil
+	})
+	alerts := &recordingAlerter{}
+	guard := NewBudgetGuard(client, BudgetLimits{Daily: 5, UserDaily: 2}, alerts)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	guard.clock = func() time.Time { return now }
+
+	sam := WithUser(context.Background(), "sam")
+	for i := 0; i < 2; i++ {
+		if _, err := guard.Extract(sam, "resume"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if _, err := guard.Extract(sam, "resume"); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("expected the user limit to stop sam, got %v", err)
+	}
+
+	ann := WithUser(context.Background(), "ann")
+	for i := 0; i < 2; i++ {
+		if _, err := guard.Extract(ann, "resume"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if _, err := guard.Extract(context.Background(), "resume"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := guard.Extract(context.Background(), "resume"); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("expected the system limit to stop extractions, got %v", err)
+	}
+	if daily, _ := guard.Spent(""); daily != 5 {
+		t.Fatalf("expected $5 spent today, got %v", daily)
+	}
+	// 80% and 100% for sam, ann and the system, each raised once.
+	if len(alerts.alerts) != 6 {
+		t.Fatalf("expected 6 alerts, got %q", alerts.alerts)
+	}
+
+	now = now.Add(24 * time.Hour)
+	if _, err := guard.Extract(sam, "resume"); err != nil {
+		t.Fatalf("expected a new day to reset the limits, got %v", err)
+	}
+}
+
+func TestBudgetGuardQueuesUntilReset(t *testing.T) {
+	client := AIClientFunc(func(context.Context, string) (Draft, error) {
+		return Draft{Cost: 1}, nil
+	})
+	guard := NewBudgetGuard(client, BudgetLimits{Monthly: 1}, nil).WithQueue()
+	now := time.Date(2024, 5, 31, 12, 0, 0, 0, time.UTC)
+	guard.clock = func() time.Time { return now }
+	var waited time.Duration
+	guard.sleep = func(_ context.Context, d time.Duration) error {
+		waited += d
+		now = now.Add(d)
+		return nil
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := guard.Extract(context.Background(), "resume"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if waited != 12*time.Hour {
+		t.Fatalf("expected to wait until June, waited %v", waited)
+	}
+}
+
+type memorySpendStore struct{ spend []spendRow }
+
+type spendRow struct {
+	userID string
+	cost   float64
+	at     time.Time
+}
+
+func (s *memorySpendStore) RecordSpend(_ context.Context, userID string, cost float64, at time.Time) error {
+	s.spend = append(s.spend, spendRow{userID, cost, at})
+	return nil
+}
+
+func (s *memorySpendStore) SpendSince(_ context.Context, since time.Time) (map[string]float64, error) {
+	spend := make(map[string]float64)
+	for _, row := range s.spend {
+		if !row.at.Before(since) {
+			spend[row.userID] += row.cost
+		}
+	}
+	return spend, nil
+}
+
+func TestBudgetGuardCountsFailedExtractionsAndLoadsSpend(t *testing.T) {
+	failing := AIClientFunc(func(context.Context, string) (Draft, error) {
+		return Draft{Cost: 0.5}, errors.New("invalid JSON after repairs")
+	})
+	working := AIClientFunc(func(context.Context, string) (Draft, error) {
+		return Draft{Profile: CandidateProfile{Name: "Sam", Skills: []string{"go"}}, Cost: 1}, nil
+	})
+	store := &memorySpendStore{}
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	chain := NewFallbackClient(quietLogger(), Provider{Name: "openai", Client: failing}, Provider{Name: "gemini", Client: working})
+	guard := NewBudgetGuard(chain, BudgetLimits{Monthly: 10}, nil).WithStore(store)
+	guard.clock = func() time.Time { return now }
+
+	sam := WithUser(context.Background(), "sam")
+	draft, err := guard.Extract(sam, "resume")
+	if err != nil || draft.Cost != 1.5 {
+		t.Fatalf("expected the skipped provider billed in the draft, got %+v (%v)", draft, err)
+	}
+	failed := NewFallbackClient(quietLogger(), Provider{Name: "openai", Client: failing})
+	guard.client = failed
+	if draft, err := guard.Extract(sam, "resume"); err == nil || draft.Cost != 0.5 {
+		t.Fatalf("expected a failed extraction with its cost, got %+v (%v)", draft, err)
+	}
+	if daily, monthly := guard.Spent("sam"); daily != 2 || monthly != 2 {
+		t.Fatalf("expected $2 spent by sam, got %v/%v", daily, monthly)
+	}
+
+	// A restart on a later day of the month starts from the saved spend.
+	now = now.Add(24 * time.Hour)
+	restarted := NewBudgetGuard(working, BudgetLimits{Monthly: 10}, nil).WithStore(store)
+	restarted.clock = func() time.Time { return now }
+	if err := restarted.LoadSpend(context.Background()); err != nil {
+		t.Fatalf("load spend: %v", err)
+	}
+	if daily, monthly := restarted.Spent(""); daily != 0 || monthly != 2 {
+		t.Fatalf("expected $2 this month and nothing today, got %v/%v", daily, monthly)
+	}
+	if _, monthly := restarted.Spent("sam"); monthly != 2 {
+		t.Fatalf("expected sam's monthly spend restored, got %v", monthly)
+	}
+}
+
+func TestBudgetGuardReservesInFlightExtractions(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	client := AIClientFunc(func(context.Context, string) (Draft, error) {
+		close(started)
+		<-release
+		return Draft{Cost: 1}, nil
+	})
+	guard := NewBudgetGuard(client, BudgetLimits{Daily: 1}, nil).WithReservation(1)
+
+	done := make(chan error)
+	go func() {
+		_, err := guard.Extract(context.Background(), "resume")
+		done <- err
+	}()
+	<-started
+	if _, err := guard.Extract(context.Background(), "resume"); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("expected the in-flight reservation to use up the limit, got %v", err)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if daily, _ := guard.Spent(""); daily != 1 {
+		t.Fatalf("expected the reservation replaced by the cost, got %v", daily)
+	}
+}
+
+func TestHasPrice(t *testing.T) {
+	for _, c := range []struct {
+		provider, model string
+		want            bool
+	}{
+		{"openai", "gpt-4o", true},
+		{"openai", "gpt-4.1", false},
+		{"openai", "", false},
+		{"gemini", "gemini-1.5-flash", true},
+		{"other", "gpt-4o", false},
+	} {
+		if got := HasPrice(c.provider, c.model); got != c.want {
+			t.Errorf("HasPrice(%q, %q) = %v, want %v", c.provider, c.model, got, c.want)
+		}
+	}
+}
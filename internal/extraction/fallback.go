@@ -0,0 +1,282 @@
+package extraction
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned for providers whose circuit breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// Reasons recorded in ProviderSkip for providers that did not answer.
+const (
+	SkipError       = "error"
+	SkipTimeout     = "timeout"
+	SkipValidation  = "validation"
+	SkipCircuitOpen = "circuit_open"
+)
+
+// ValidationError reports a model response that is not valid JSON or does not
+// match the CandidateProfile schema.
+type ValidationError struct {
+	// Raw is the response that failed.
+	Raw string
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// Provider is an AIClient in a FallbackClient chain.
+type Provider struct {
+	// Name identifies the provider in Draft.Provider, e.g. "openai".
+	Name   string
+	Client AIClient
+	// Timeout bounds one Extract call; zero leaves it to the caller's context.
+	Timeout time.Duration
+}
+
+// NewProvider builds the AIClient for a provider named in AI_PROVIDER or
+// AI_FALLBACK_PROVIDER, priced with DefaultOpenAICosts or DefaultGeminiCost.
+// Usage is reported to observer when it is not nil.
+func NewProvider(ctx context.Context, name, apiKey, model string, logger *log.Logger, observer Observer) (Provider, error) {
+	switch name {
+	case "openai":
+		client := NewOpenAIClient(apiKey, model, 0, 0, logger, nil, DefaultOpenAICosts)
+		if observer != nil {
+			client.WithObserver(observer)
+		}
+		return Provider{Name: name, Client: client}, nil
+	case "gemini":
+		client, err := NewGeminiClient(ctx, apiKey, model, 0, 0, logger, nil, DefaultGeminiCost)
+		if err != nil {
+			return Provider{}, err
+		}
+		if observer != nil {
+			client.WithObserver(observer)
+		}
+		return Provider{Name: name, Client: client}, nil
+	default:
+		return Provider{}, fmt.Errorf("unsupported AI provider %q", name)
+	}
+}
+
+// FallbackClient is an AIClient that tries each provider in turn until one
+// returns a valid draft. Providers that keep failing are skipped for a while
+// by a circuit breaker of their own.
+type FallbackClient struct {
+	providers []fallbackProvider
+	validator Validator
+	logger    *log.Logger
+}
+
+type fallbackProvider struct {
+	Provider
+	breaker *CircuitBreaker
+}
+
+// NewFallbackClient constructs a chain over providers, in order of
+// preference. Each provider's breaker opens after 3 failures in a row and
+// lets a trial call through after a minute; see WithBreaker.
+func NewFallbackClient(logger *log.Logger, providers ...Provider) *FallbackClient {
+	if logger == nil {
+		logger = log.Default()
+	}
+	c := &FallbackClient{validator: defaultValidator(), logger: logger}
+	for _, p := range providers {
+		c.providers = append(c.providers, fallbackProvider{Provider: p, breaker: NewCircuitBreaker(3, time.Minute)})
+	}
+	return c
+}
+
+// WithBreaker replaces every provider's circuit breaker with one that opens
+// after failures consecutive failures and stays open for cooldown.
+func (c *FallbackClient) WithBreaker(failures int, cooldown time.Duration) *FallbackClient {
+	for i := range c.providers {
+		c.providers[i].breaker = NewCircuitBreaker(failures, cooldown)
+	}
+	return c
+}
+
+// Breaker returns the circuit breaker of the named provider, or nil.
+func (c *FallbackClient) Breaker(name string) *CircuitBreaker {
+	for _, p := range c.providers {
+		if p.Name == name {
+			return p.breaker
+		}
+	}
+	return nil
+}
+
+// Extract implements AIClient. The returned draft names the provider that
+// answered and why each earlier provider was skipped, and its Cost includes
+// the calls made to the skipped providers.
+func (c *FallbackClient) Extract(ctx context.Context, sourceText string) (Draft, error) {
+	var (
+		skipped []ProviderSkip
+		errs    []error
+		cost    float64
+	)
+	for _, p := range c.providers {
+		if err := ctx.Err(); err != nil {
+			return Draft{Skipped: skipped, Cost: cost}, err
+		}
+		if !p.breaker.Allow() {
+			skipped = append(skipped, ProviderSkip{Provider: p.Name, Reason: SkipCircuitOpen, Error: ErrCircuitOpen.Error()})
+			errs = append(errs, fmt.Errorf("%s: %w", p.Name, ErrCircuitOpen))
+			continue
+		}
+
+		draft, err := c.extract(ctx, p.Provider, sourceText)
+		cost += draft.Cost
+		if err == nil {
+			p.breaker.Success()
+			draft.Provider = p.Name
+			draft.Skipped = skipped
+			draft.Cost = cost
+			return draft, nil
+		}
+		if ctx.Err() != nil {
+			// The caller gave up; that says nothing about the provider.
+			p.breaker.abort()
+			return Draft{Skipped: skipped, Cost: cost}, ctx.Err()
+		}
+
+		p.breaker.Failure()
+		reason := skipReason(err)
+		c.logger.Printf("extraction provider %s skipped (%s): %v", p.Name, reason, err)
+		skipped = append(skipped, ProviderSkip{Provider: p.Name, Reason: reason, Error: err.Error()})
+		errs = append(errs, fmt.Errorf("%s: %w", p.Name, err))
+	}
+	if len(errs) == 0 {
+		return Draft{}, errors.New("no extraction providers configured")
+	}
+	return Draft{Skipped: skipped, Cost: cost}, fmt.Errorf("all extraction providers failed: %w", errors.Join(errs...))
+}
+
+// extract calls one provider. On error the draft only carries the cost of
+// the calls made.
+func (c *FallbackClient) extract(ctx context.Context, p Provider, sourceText string) (Draft, error) {
+	if p.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.Timeout)
+		defer cancel()
+	}
+	draft, err := p.Client.Extract(ctx, sourceText)
+	if err != nil {
+		return Draft{Cost: draft.Cost}, err
+	}
+	// Providers outside this package may not validate their answers.
+	if err := c.validator.Struct(draft.Profile); err != nil {
+		return Draft{Cost: draft.Cost}, &ValidationError{Raw: draft.RawResponse, Err: fmt.Errorf("validation: %w", err)}
+	}
+	return draft, nil
+}
+
+func skipReason(err error) string {
+	var invalid *ValidationError
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return SkipTimeout
+	case errors.As(err, &invalid):
+		return SkipValidation
+	default:
+		return SkipError
+	}
+}
+
+// BreakerState is the state of a CircuitBreaker.
+type BreakerState string
+
+const (
+	BreakerClosed   BreakerState = "closed"
+	BreakerOpen     BreakerState = "open"
+	BreakerHalfOpen BreakerState = "half_open"
+)
+
+// CircuitBreaker stops calls to a provider after repeated failures. Once the
+// cooldown has passed, one trial call is let through: success closes the
+// breaker again and failure keeps it open for another cooldown.
+type CircuitBreaker struct {
+	mu       sync.Mutex
+	failures int
+	limit    int
+	cooldown time.Duration
+	openedAt time.Time
+	state    BreakerState
+	clock    func() time.Time
+}
+
+// NewCircuitBreaker constructs a closed breaker that opens after limit
+// consecutive failures.
+func NewCircuitBreaker(limit int, cooldown time.Duration) *CircuitBreaker {
+	if limit < 1 {
+		limit = 1
+	}
+	return &CircuitBreaker{limit: limit, cooldown: cooldown, state: BreakerClosed, clock: time.Now}
+}
+
+// Allow reports whether a call may go through now.
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case BreakerOpen:
+		if b.clock().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = BreakerHalfOpen
+		return true
+	case BreakerHalfOpen:
+		// A trial call is already in flight.
+		return false
+	default:
+		return true
+	}
+}
+
+// Success records a successful call and closes the breaker.
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.state = BreakerClosed
+}
+
+// Failure records a failed call, opening the breaker at the limit or when a
+// trial call fails.
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= b.limit {
+		b.state = BreakerOpen
+		b.openedAt = b.clock()
+	}
+}
+
+// abort ends a call that neither succeeded nor failed. An abandoned trial
+// call leaves the breaker open but ready for the next trial.
+func (b *CircuitBreaker) abort() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == BreakerHalfOpen {
+		b.state = BreakerOpen
+	}
+}
+
+// State returns the breaker's current state.
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
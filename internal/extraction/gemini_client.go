@@ -17,10 +17,11 @@ type GeminiClient struct {
 	client    *genai.Client
 	model     string
 	maxTokens int
-	retries   int
+	retry     Retry
 	logger    *log.Logger
 	validator Validator
 	cost      TokenCost
+	observer  Observer
 }
 
 // NewGeminiClient builds a configured Gemini client with guardrails.
@@ -46,78 +47,122 @@ func NewGeminiClient(ctx context.Context, apiKey, model string, maxTokens, retri
 		client:    cl,
 		model:     model,
 		maxTokens: maxTokens,
-		retries:   retries,
+		retry:     DefaultRetry(retries),
 		logger:    logger,
 		validator: validator,
 		cost:      cost,
 	}, nil
 }
 
+// WithObserver reports token usage, cost, latency, retries and validation
+// failures to observer.
+func (c *GeminiClient) WithObserver(observer Observer) *GeminiClient {
+	c.observer = observer
+	c.retry = observeRetries(c.retry, observer, "gemini", c.model)
+	return c
+}
+
 // Extract requests structured content from Gemini, validates it, and returns the draft payload.
 func (c *GeminiClient) Extract(ctx context.Context, sourceText string) (Draft, error) {
 	prompt := structuredPrompt(sourceText)
-	var lastErr error
-	backoff := 250 * time.Millisecond
-
-	for attempt := 1; attempt <= c.retries; attempt++ {
+	var (
+		draft    Draft
+		invalid  *ValidationError
+		rejected []string
+		cost     float64
+	)
+
+	attempts, err := c.retry.Do(ctx, c.logger, "gemini", func(ctx context.Context, _ int) error {
 		start := time.Now()
 		model := c.client.GenerativeModel(c.model)
-		model.ResponseMimeType = "application/json"
-		model.GenerationConfig = &genai.GenerationConfig{MaxOutputTokens: int32(c.maxTokens)}
+		model.ResponseMIMEType = "application/json"
+		model.SetMaxOutputTokens(int32(c.maxTokens))
 		model.SystemInstruction = &genai.Content{Parts: []genai.Part{genai.Text("You are a structured resume parser that outputs compact JSON.")}}
 
-		resp, err := model.GenerateContent(ctx, genai.Text(prompt))
+		var (
+			resp *genai.GenerateContentResponse
+			err  error
+		)
+		if invalid != nil {
+			// Ask for a fix of the last rejected answer instead of starting over.
+			chat := model.StartChat()
+			chat.History = []*genai.Content{
+				{Role: "user", Parts: []genai.Part{genai.Text(prompt)}},
+				{Role: "model", Parts: []genai.Part{genai.Text(invalid.Raw)}},
+			}
+			resp, err = chat.SendMessage(ctx, genai.Text(repairPrompt(invalid)))
+		} else {
+			resp, err = model.GenerateContent(ctx, genai.Text(prompt))
+		}
 		latency := time.Since(start)
-
 		if err != nil {
-			lastErr = err
-			c.logger.Printf("gemini attempt %d failed: %v", attempt, err)
-			time.Sleep(backoff)
-			backoff *= 2
-			continue
+			return err
 		}
-
-		if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
-			lastErr = errors.New("gemini returned no content")
-			c.logger.Printf("gemini attempt %d empty content", attempt)
-			time.Sleep(backoff)
-			backoff *= 2
-			continue
+		// Every answer is billed, including the ones rejected below.
+		cost += c.logUsage(latency, resp.UsageMetadata)
+		if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil || len(resp.Candidates[0].Content.Parts) == 0 {
+			return errors.New("gemini returned no content")
 		}
-
 		textPart, ok := resp.Candidates[0].Content.Parts[0].(genai.Text)
 		if !ok {
-			lastErr = errors.New("gemini response not text")
-			c.logger.Printf("gemini attempt %d unexpected part type", attempt)
-			time.Sleep(backoff)
-			backoff *= 2
-			continue
+			return errors.New("gemini response not text")
 		}
 
-		draft, err := c.toDraft(string(textPart), c.model)
+		draft, err = c.toDraft(string(textPart), c.model)
+		if errors.As(err, &invalid) {
+			rejected = append(rejected, invalid.Raw)
+			if c.observer != nil {
+				c.observer.ObserveValidationFailure("gemini", c.model)
+			}
+		}
 		if err != nil {
-			lastErr = err
-			c.logger.Printf("gemini attempt %d validation failed: %v", attempt, err)
-			time.Sleep(backoff)
-			backoff *= 2
-			continue
+			return err
 		}
+		return nil
+	})
+	if err != nil {
+		// The calls made are billed even though no valid draft came back.
+		return Draft{Attempts: attempts, Rejected: rejected, Cost: cost}, fmt.Errorf("gemini extraction failed after %d attempts: %w", attempts, err)
+	}
+	draft.Attempts = attempts
+	draft.Rejected = rejected
+	draft.Cost = cost
+	return draft, nil
+}
+
+// Complete sends a free-form prompt and returns the generated text together
+// with the model name. It serves tasks such as vacancy summaries that do not
+// use the extraction schema.
+func (c *GeminiClient) Complete(ctx context.Context, system, prompt string) (string, string, error) {
+	start := time.Now()
+	model := c.client.GenerativeModel(c.model)
+	model.SetMaxOutputTokens(int32(c.maxTokens))
+	model.SystemInstruction = &genai.Content{Parts: []genai.Part{genai.Text(system)}}
 
-		c.logUsage(latency, resp.UsageMetadata)
-		return draft, nil
+	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
+	if err != nil {
+		return "", "", fmt.Errorf("gemini completion: %w", err)
+	}
+	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil || len(resp.Candidates[0].Content.Parts) == 0 {
+		return "", "", errors.New("gemini returned no content")
+	}
+	textPart, ok := resp.Candidates[0].Content.Parts[0].(genai.Text)
+	if !ok {
+		return "", "", errors.New("gemini response not text")
 	}
 
-	return Draft{}, fmt.Errorf("gemini extraction failed after %d attempts: %w", c.retries, lastErr)
+	c.logUsage(time.Since(start), resp.UsageMetadata)
+	return string(textPart), c.model, nil
 }
 
 func (c *GeminiClient) toDraft(raw, model string) (Draft, error) {
 	var profile CandidateProfile
 	if err := json.Unmarshal([]byte(raw), &profile); err != nil {
-		return Draft{}, fmt.Errorf("decode: %w", err)
+		return Draft{}, &ValidationError{Raw: raw, Err: fmt.Errorf("decode: %w", err)}
 	}
 
 	if err := c.validator.Struct(profile); err != nil {
-		return Draft{}, fmt.Errorf("validation: %w", err)
+		return Draft{}, &ValidationError{Raw: raw, Err: fmt.Errorf("validation: %w", err)}
 	}
 
 	return Draft{
@@ -128,13 +173,16 @@ func (c *GeminiClient) toDraft(raw, model string) (Draft, error) {
 	}, nil
 }
 
-func (c *GeminiClient) logUsage(latency time.Duration, usage *genai.UsageMetadata) {
-	var promptTokens, outputTokens int32
+func (c *GeminiClient) logUsage(latency time.Duration, usage *genai.UsageMetadata) float64 {
+	var promptTokens, outputTokens int
 	if usage != nil {
-		promptTokens = usage.PromptTokenCount
-		outputTokens = usage.CandidatesTokenCount
+		promptTokens = int(usage.PromptTokenCount)
+		outputTokens = int(usage.CandidatesTokenCount)
+	}
+	cost := c.cost.Estimate(promptTokens, outputTokens)
+	c.logger.Printf("gemini model=%s latency_ms=%d prompt_tokens=%d completion_tokens=%d estimated_cost=%.6f", c.model, latency.Milliseconds(), promptTokens, outputTokens, cost)
+	if c.observer != nil {
+		c.observer.ObserveCall("gemini", c.model, latency, promptTokens, outputTokens, cost)
 	}
-	inputCost := (float64(promptTokens) / 1000.0) * c.cost.InputCost
-	outputCost := (float64(outputTokens) / 1000.0) * c.cost.OutputCost
-	c.logger.Printf("gemini model=%s latency_ms=%d prompt_tokens=%d completion_tokens=%d estimated_cost=%.6f", c.model, latency.Milliseconds(), promptTokens, outputTokens, inputCost+outputCost)
+	return cost
 }
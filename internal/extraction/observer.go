@@ -0,0 +1,24 @@
+package extraction
+
+import "time"
+
+// Observer receives the usage of provider calls, e.g. to export it as
+// Prometheus metrics. provider is "openai" or "gemini".
+type Observer interface {
+	// ObserveCall records a call that returned an answer, valid or not, and
+	// its estimated cost.
+	ObserveCall(provider, model string, latency time.Duration, promptTokens, completionTokens int, cost float64)
+	// ObserveRetry records a failed attempt that is retried, with its
+	// ErrorClass.
+	ObserveRetry(provider, model, class string)
+	// ObserveValidationFailure records a response that failed validation.
+	ObserveValidationFailure(provider, model string)
+}
+
+// observeRetries reports the retries of r to observer.
+func observeRetries(r Retry, observer Observer, provider, model string) Retry {
+	r.OnRetry = func(class ErrorClass) {
+		observer.ObserveRetry(provider, model, class.String())
+	}
+	return r
+}
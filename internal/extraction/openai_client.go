@@ -16,10 +16,11 @@ type OpenAIClient struct {
 	client     *openai.Client
 	model      string
 	maxTokens  int
-	retries    int
+	retry      Retry
 	logger     *log.Logger
 	validator  Validator
 	costConfig map[string]TokenCost
+	observer   Observer
 }
 
 // TokenCost represents input/output token prices per 1K tokens.
@@ -51,77 +52,122 @@ func NewOpenAIClient(apiKey, model string, maxTokens, retries int, logger *log.L
 		client:     openai.NewClient(apiKey),
 		model:      model,
 		maxTokens:  maxTokens,
-		retries:    retries,
+		retry:      DefaultRetry(retries),
 		logger:     logger,
 		validator:  validator,
 		costConfig: costConfig,
 	}
 }
 
+// WithObserver reports token usage, cost, latency, retries and validation
+// failures to observer.
+func (c *OpenAIClient) WithObserver(observer Observer) *OpenAIClient {
+	c.observer = observer
+	c.retry = observeRetries(c.retry, observer, "openai", c.model)
+	return c
+}
+
 // Extract requests a structured completion, validates it against the schema, and
 // returns a Draft enriched with metadata.
 func (c *OpenAIClient) Extract(ctx context.Context, sourceText string) (Draft, error) {
 	prompt := structuredPrompt(sourceText)
-	var lastErr error
-	backoff := 250 * time.Millisecond
+	var (
+		draft    Draft
+		invalid  *ValidationError
+		rejected []string
+		cost     float64
+	)
+
+	attempts, err := c.retry.Do(ctx, c.logger, "openai", func(ctx context.Context, _ int) error {
+		messages := []openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleSystem, Content: "You are a structured resume parser that outputs compact JSON."},
+			{Role: openai.ChatMessageRoleUser, Content: prompt},
+		}
+		if invalid != nil {
+			// Ask for a fix of the last rejected answer instead of starting over.
+			messages = append(messages,
+				openai.ChatCompletionMessage{Role: openai.ChatMessageRoleAssistant, Content: invalid.Raw},
+				openai.ChatCompletionMessage{Role: openai.ChatMessageRoleUser, Content: repairPrompt(invalid)},
+			)
+		}
 
-	for attempt := 1; attempt <= c.retries; attempt++ {
 		start := time.Now()
 		resp, err := c.client.CreateChatCompletion(
 			ctx,
 			openai.ChatCompletionRequest{
-				Model: c.model,
-				Messages: []openai.ChatCompletionMessage{
-					{Role: openai.ChatMessageRoleSystem, Content: "You are a structured resume parser that outputs compact JSON."},
-					{Role: openai.ChatMessageRoleUser, Content: prompt},
-				},
+				Model:          c.model,
+				Messages:       messages,
 				ResponseFormat: &openai.ChatCompletionResponseFormat{Type: openai.ChatCompletionResponseFormatTypeJSONObject},
 				MaxTokens:      c.maxTokens,
 			},
 		)
 		latency := time.Since(start)
-
 		if err != nil {
-			lastErr = err
-			c.logger.Printf("openai attempt %d failed: %v", attempt, err)
-			time.Sleep(backoff)
-			backoff *= 2
-			continue
+			return err
 		}
-
+		// Every answer is billed, including the ones rejected below.
+		cost += c.logUsage(latency, resp.Usage)
 		if len(resp.Choices) == 0 {
-			lastErr = errors.New("openai returned no choices")
-			c.logger.Printf("openai attempt %d empty choices", attempt)
-			time.Sleep(backoff)
-			backoff *= 2
-			continue
+			return errors.New("openai returned no choices")
 		}
 
-		content := resp.Choices[0].Message.Content
-		draft, err := c.toDraft(content, resp.Model)
+		draft, err = c.toDraft(resp.Choices[0].Message.Content, resp.Model)
+		if errors.As(err, &invalid) {
+			rejected = append(rejected, invalid.Raw)
+			if c.observer != nil {
+				c.observer.ObserveValidationFailure("openai", c.model)
+			}
+		}
 		if err != nil {
-			lastErr = err
-			c.logger.Printf("openai attempt %d validation failed: %v", attempt, err)
-			time.Sleep(backoff)
-			backoff *= 2
-			continue
+			return err
 		}
+		return nil
+	})
+	if err != nil {
+		// The calls made are billed even though no valid draft came back.
+		return Draft{Attempts: attempts, Rejected: rejected, Cost: cost}, fmt.Errorf("openai extraction failed after %d attempts: %w", attempts, err)
+	}
+	draft.Attempts = attempts
+	draft.Rejected = rejected
+	draft.Cost = cost
+	return draft, nil
+}
 
-		c.logUsage(latency, resp.Usage)
-		return draft, nil
+// Complete sends a free-form prompt and returns the generated text together
+// with the model that answered. It serves tasks such as vacancy summaries that
+// do not use the extraction schema.
+func (c *OpenAIClient) Complete(ctx context.Context, system, prompt string) (string, string, error) {
+	start := time.Now()
+	resp, err := c.client.CreateChatCompletion(
+		ctx,
+		openai.ChatCompletionRequest{
+			Model: c.model,
+			Messages: []openai.ChatCompletionMessage{
+				{Role: openai.ChatMessageRoleSystem, Content: system},
+				{Role: openai.ChatMessageRoleUser, Content: prompt},
+			},
+			MaxTokens: c.maxTokens,
+		},
+	)
+	if err != nil {
+		return "", "", fmt.Errorf("openai completion: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return "", "", errors.New("openai returned no choices")
 	}
 
-	return Draft{}, fmt.Errorf("openai extraction failed after %d attempts: %w", c.retries, lastErr)
+	c.logUsage(time.Since(start), resp.Usage)
+	return resp.Choices[0].Message.Content, resp.Model, nil
 }
 
 func (c *OpenAIClient) toDraft(raw, model string) (Draft, error) {
 	var profile CandidateProfile
 	if err := json.Unmarshal([]byte(raw), &profile); err != nil {
-		return Draft{}, fmt.Errorf("decode: %w", err)
+		return Draft{}, &ValidationError{Raw: raw, Err: fmt.Errorf("decode: %w", err)}
 	}
 
 	if err := c.validator.Struct(profile); err != nil {
-		return Draft{}, fmt.Errorf("validation: %w", err)
+		return Draft{}, &ValidationError{Raw: raw, Err: fmt.Errorf("validation: %w", err)}
 	}
 
 	return Draft{
@@ -132,12 +178,11 @@ func (c *OpenAIClient) toDraft(raw, model string) (Draft, error) {
 	}, nil
 }
 
-func (c *OpenAIClient) logUsage(latency time.Duration, usage openai.Usage) {
-	cost, ok := c.costConfig[c.model]
-	if !ok {
-		cost = TokenCost{}
+func (c *OpenAIClient) logUsage(latency time.Duration, usage openai.Usage) float64 {
+	cost := c.costConfig[c.model].Estimate(usage.PromptTokens, usage.CompletionTokens)
+	c.logger.Printf("openai model=%s latency_ms=%d prompt_tokens=%d completion_tokens=%d estimated_cost=%.6f", c.model, latency.Milliseconds(), usage.PromptTokens, usage.CompletionTokens, cost)
+	if c.observer != nil {
+		c.observer.ObserveCall("openai", c.model, latency, usage.PromptTokens, usage.CompletionTokens, cost)
 	}
-	inputCost := (float64(usage.PromptTokens) / 1000.0) * cost.InputCost
-	outputCost := (float64(usage.CompletionTokens) / 1000.0) * cost.OutputCost
-	c.logger.Printf("openai model=%s latency_ms=%d prompt_tokens=%d completion_tokens=%d estimated_cost=%.6f", c.model, latency.Milliseconds(), usage.PromptTokens, usage.CompletionTokens, inputCost+outputCost)
+	return cost
 }
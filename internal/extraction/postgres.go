@@ -0,0 +1,53 @@
+package extraction
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresSpendStore keeps the spend of a BudgetGuard in the ai_spend table.
+type PostgresSpendStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresSpendStore constructs a spend store backed by the given pool.
+func NewPostgresSpendStore(pool *pgxpool.Pool) *PostgresSpendStore {
+	return &PostgresSpendStore{pool: pool}
+}
+
+// RecordSpend implements SpendStore.
+func (s *PostgresSpendStore) RecordSpend(ctx context.Context, userID string, cost float64, at time.Time) error {
+	_, err := s.pool.Exec(ctx, `INSERT INTO ai_spend (user_id, cost_usd, created_at) VALUES ($1, $2, $3)`, userID, cost, at)
+	if err != nil {
+		return fmt.Errorf("record AI spend: %w", err)
+	}
+	return nil
+}
+
+// SpendSince implements SpendStore.
+func (s *PostgresSpendStore) SpendSince(ctx context.Context, since time.Time) (map[string]float64, error) {
+	rows, err := s.pool.Query(ctx, `SELECT user_id, SUM(cost_usd) FROM ai_spend WHERE created_at >= $1 GROUP BY user_id`, since)
+	if err != nil {
+		return nil, fmt.Errorf("query AI spend: %w", err)
+	}
+	defer rows.Close()
+
+	spend := make(map[string]float64)
+	for rows.Next() {
+		var (
+			userID string
+			cost   float64
+		)
+		if err := rows.Scan(&userID, &cost); err != nil {
+			return nil, fmt.Errorf("scan AI spend: %w", err)
+		}
+		spend[userID] = cost
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read AI spend: %w", err)
+	}
+	return spend, nil
+}
@@ -28,3 +28,9 @@ func structuredPrompt(source string) string {
 
 	return fmt.Sprintf("%s\nExpected schema:%s\n\nSource:\n%s", strings.Join(instructions, " "), schema, source)
 }
+
+// repairPrompt asks the model to correct a response that failed validation,
+// quoting the validator errors and the rejected JSON.
+func repairPrompt(invalid *ValidationError) string {
+	return fmt.Sprintf("Your previous response was rejected: %s\n\nRejected JSON:\n%s\n\nReturn the corrected candidate profile as valid JSON only, following the expected schema.", invalid.Err, invalid.Raw)
+}
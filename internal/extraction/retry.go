@@ -0,0 +1,244 @@
+package extraction
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+	"google.golang.org/api/googleapi"
+)
+
+// ErrorClass groups provider errors by how Retry treats them.
+type ErrorClass int
+
+const (
+	// ErrorRetryable covers network failures, malformed answers and anything
+	// not classified otherwise.
+	ErrorRetryable ErrorClass = iota
+	// ErrorRateLimited means the provider asked us to slow down.
+	ErrorRateLimited
+	// ErrorServer covers 5xx responses and unavailable providers.
+	ErrorServer
+	// ErrorInvalidRequest covers requests the provider rejects as malformed,
+	// which fail the same way on every retry.
+	ErrorInvalidRequest
+	// ErrorAuth covers missing or rejected credentials.
+	ErrorAuth
+)
+
+func (c ErrorClass) String() string {
+	switch c {
+	case ErrorRateLimited:
+		return "rate_limited"
+	case ErrorServer:
+		return "server_error"
+	case ErrorInvalidRequest:
+		return "invalid_request"
+	case ErrorAuth:
+		return "auth"
+	default:
+		return "retryable"
+	}
+}
+
+// Retryable reports whether another attempt may succeed.
+func (c ErrorClass) Retryable() bool {
+	return c != ErrorInvalidRequest && c != ErrorAuth
+}
+
+// grpcCodePattern finds the status code in gRPC error strings such as
+// "rpc error: code = ResourceExhausted desc = ...".
+var grpcCodePattern = regexp.MustCompile(`code = (\w+)`)
+
+var grpcClasses = map[string]ErrorClass{
+	"ResourceExhausted":  ErrorRateLimited,
+	"Unavailable":        ErrorServer,
+	"Internal":           ErrorServer,
+	"DeadlineExceeded":   ErrorServer,
+	"InvalidArgument":    ErrorInvalidRequest,
+	"FailedPrecondition": ErrorInvalidRequest,
+	"NotFound":           ErrorInvalidRequest,
+	"Unauthenticated":    ErrorAuth,
+	"PermissionDenied":   ErrorAuth,
+}
+
+// ClassifyError is the default classifier for Retry. It understands the
+// OpenAI and Google API error types, gRPC status strings and HTTP status
+// codes carried by other errors.
+func ClassifyError(err error) ErrorClass {
+	if code := statusCode(err); code != 0 {
+		return classifyStatus(code)
+	}
+	if match := grpcCodePattern.FindStringSubmatch(err.Error()); match != nil {
+		if class, ok := grpcClasses[match[1]]; ok {
+			return class
+		}
+	}
+	return ErrorRetryable
+}
+
+func classifyStatus(code int) ErrorClass {
+	switch {
+	case code == http.StatusTooManyRequests:
+		return ErrorRateLimited
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		return ErrorAuth
+	case code >= 500:
+		return ErrorServer
+	case code >= 400 && code != http.StatusRequestTimeout && code != http.StatusConflict:
+		return ErrorInvalidRequest
+	default:
+		return ErrorRetryable
+	}
+}
+
+// statusCode returns the HTTP status of a provider error, or 0.
+func statusCode(err error) int {
+	var apiErr *openai.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.HTTPStatusCode
+	}
+	var reqErr *openai.RequestError
+	if errors.As(err, &reqErr) {
+		return reqErr.HTTPStatusCode
+	}
+	var googleErr *googleapi.Error
+	if errors.As(err, &googleErr) {
+		return googleErr.Code
+	}
+	var httpErr interface{ HTTPCode() int }
+	if errors.As(err, &httpErr) && httpErr.HTTPCode() > 0 {
+		return httpErr.HTTPCode()
+	}
+	return 0
+}
+
+// retryAfterPattern matches the wait providers put in rate-limit messages,
+// such as OpenAI's "Please try again in 20s" or Gemini's "Please retry in
+// 42.5s".
+var retryAfterPattern = regexp.MustCompile(`(?i)(?:retry after|try again in|retry in) (\d+(?:\.\d+)?)\s*(ms|s\b|sec|seconds?)?`)
+
+// retryAfter extracts the wait requested by a rate-limited provider, from a
+// Retry-After header or the error message.
+func retryAfter(err error) (time.Duration, bool) {
+	var googleErr *googleapi.Error
+	if errors.As(err, &googleErr) && googleErr.Header != nil {
+		if value := googleErr.Header.Get("Retry-After"); value != "" {
+			if seconds, convErr := strconv.Atoi(value); convErr == nil {
+				return time.Duration(seconds) * time.Second, true
+			}
+			if at, parseErr := http.ParseTime(value); parseErr == nil {
+				return time.Until(at), true
+			}
+		}
+	}
+
+	match := retryAfterPattern.FindStringSubmatch(err.Error())
+	if match == nil {
+		return 0, false
+	}
+	value, convErr := strconv.ParseFloat(match[1], 64)
+	if convErr != nil {
+		return 0, false
+	}
+	unit := time.Second
+	if strings.EqualFold(match[2], "ms") {
+		unit = time.Millisecond
+	}
+	return time.Duration(value * float64(unit)), true
+}
+
+// Retry runs a provider call until it succeeds, the error is not retryable or
+// the attempts run out. Waits double from Base up to Max, rate-limited calls
+// wait as long as the provider asks, and every wait ends early when the
+// context is done.
+type Retry struct {
+	Attempts int
+	Base     time.Duration
+	Max      time.Duration
+	// Classify decides which errors are retried. Defaults to ClassifyError.
+	Classify func(error) ErrorClass
+	// OnRetry, when set, is called for each failed attempt that is retried.
+	OnRetry func(class ErrorClass)
+
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// DefaultRetry returns the policy the provider clients use for the given
+// number of attempts.
+func DefaultRetry(attempts int) Retry {
+	return Retry{Attempts: attempts, Base: 250 * time.Millisecond, Max: 10 * time.Second}
+}
+
+// Do calls fn with the 1-based attempt number and returns the number of
+// attempts made. Failed attempts are logged under name.
+func (r Retry) Do(ctx context.Context, logger *log.Logger, name string, fn func(ctx context.Context, attempt int) error) (int, error) {
+	classify := r.Classify
+	if classify == nil {
+		classify = ClassifyError
+	}
+	sleep := r.sleep
+	if sleep == nil {
+		sleep = sleepContext
+	}
+	attempts := r.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	wait := r.Base
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx, attempt)
+		if err == nil {
+			return attempt, nil
+		}
+		if ctx.Err() != nil {
+			return attempt, err
+		}
+		class := classify(err)
+		if logger != nil {
+			logger.Printf("%s attempt %d failed (%s): %v", name, attempt, class, err)
+		}
+		if !class.Retryable() || attempt >= attempts {
+			return attempt, err
+		}
+
+		if r.OnRetry != nil {
+			r.OnRetry(class)
+		}
+		delay := wait
+		if class == ErrorRateLimited {
+			if hint, ok := retryAfter(err); ok {
+				delay = hint
+			}
+		}
+		if sleepErr := sleep(ctx, delay); sleepErr != nil {
+			return attempt, errors.Join(err, sleepErr)
+		}
+		if wait *= 2; r.Max > 0 && wait > r.Max {
+			wait = r.Max
+		}
+	}
+}
+
+// sleepContext waits for d or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
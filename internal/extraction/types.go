@@ -23,4 +23,24 @@ type Draft struct {
 	RawResponse string           `json:"raw_response"`
 	Model       string           `json:"model"`
 	ExtractedAt time.Time        `json:"extracted_at"`
+	// Provider names the FallbackClient provider that answered, and Skipped
+	// lists the providers tried before it.
+	Provider string         `json:"provider,omitempty"`
+	Skipped  []ProviderSkip `json:"skipped,omitempty"`
+	// Attempts is the number of calls the provider client needed.
+	Attempts int `json:"attempts,omitempty"`
+	// Rejected keeps, in order, the raw responses that failed validation
+	// before the model repaired them.
+	Rejected []string `json:"rejected,omitempty"`
+	// Cost is the estimated USD cost of the provider calls behind the draft,
+	// rejected answers and providers skipped by a FallbackClient included.
+	Cost float64 `json:"cost,omitempty"`
+}
+
+// ProviderSkip records why a FallbackClient moved past a provider.
+type ProviderSkip struct {
+	Provider string `json:"provider"`
+	// Reason is SkipError, SkipTimeout, SkipValidation or SkipCircuitOpen.
+	Reason string `json:"reason"`
+	Error  string `json:"error,omitempty"`
 }
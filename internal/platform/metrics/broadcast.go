@@ -0,0 +1,64 @@
+package metrics
+
+import "github.com/prometheus/client_golang/prometheus"
+
+// BroadcastMetrics exports vacancy engagement per channel and company, and per
+// published vacancy. The per-vacancy series are deleted once a broadcast is
+// retracted or expires, so their number follows the open vacancies.
+type BroadcastMetrics struct {
+	clicks        *prometheus.CounterVec
+	views         *prometheus.GaugeVec
+	vacancyClicks *prometheus.CounterVec
+	vacancyViews  *prometheus.GaugeVec
+}
+
+// NewBroadcastMetrics registers the broadcast engagement metrics.
+func NewBroadcastMetrics(r *Registry) *BroadcastMetrics {
+	labels := []string{"channel", "company"}
+	m := &BroadcastMetrics{
+		clicks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "golangjobsuz_broadcast_apply_clicks_total",
+			Help: "Clicks on tracked apply links of broadcast vacancies.",
+		}, labels),
+		views: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "golangjobsuz_broadcast_views",
+			Help: "Views of the broadcast posts refreshed by the last view collection.",
+		}, labels),
+		vacancyClicks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "golangjobsuz_broadcast_vacancy_apply_clicks_total",
+			Help: "Clicks on the tracked apply link of a published vacancy.",
+		}, []string{"record_id"}),
+		vacancyViews: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "golangjobsuz_broadcast_vacancy_views",
+			Help: "Views of a published vacancy's posts at the last view collection.",
+		}, []string{"record_id"}),
+	}
+	r.Registerer().MustRegister(m.clicks, m.views, m.vacancyClicks, m.vacancyViews)
+	return m
+}
+
+// ObserveClick counts an apply-link click.
+func (m *BroadcastMetrics) ObserveClick(channel, company string) {
+	m.clicks.WithLabelValues(channel, company).Inc()
+}
+
+// ObserveViews records the views of a channel's and company's broadcasts.
+func (m *BroadcastMetrics) ObserveViews(channel, company string, views int) {
+	m.views.WithLabelValues(channel, company).Set(float64(views))
+}
+
+// ObserveVacancyClick counts an apply-link click on a published vacancy.
+func (m *BroadcastMetrics) ObserveVacancyClick(recordID string) {
+	m.vacancyClicks.WithLabelValues(recordID).Inc()
+}
+
+// ObserveVacancyViews records the views of a published vacancy.
+func (m *BroadcastMetrics) ObserveVacancyViews(recordID string, views int) {
+	m.vacancyViews.WithLabelValues(recordID).Set(float64(views))
+}
+
+// ForgetVacancy deletes the series of a retracted or expired vacancy.
+func (m *BroadcastMetrics) ForgetVacancy(recordID string) {
+	m.vacancyClicks.DeleteLabelValues(recordID)
+	m.vacancyViews.DeleteLabelValues(recordID)
+}
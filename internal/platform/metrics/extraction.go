@@ -0,0 +1,92 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// ExtractionMetrics exports the usage of AI providers. It implements
+// extraction.Observer.
+type ExtractionMetrics struct {
+	tokens  *prometheus.CounterVec
+	cost    *prometheus.CounterVec
+	latency *prometheus.HistogramVec
+	retries *prometheus.CounterVec
+	invalid *prometheus.CounterVec
+}
+
+// NewExtractionMetrics registers the AI usage metrics.
+func NewExtractionMetrics(r *Registry) *ExtractionMetrics {
+	m := &ExtractionMetrics{
+		tokens: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "golangjobsuz_ai_tokens_total",
+			Help: "Tokens used by AI calls, by direction (input or output).",
+		}, []string{"provider", "model", "direction"}),
+		cost: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "golangjobsuz_ai_cost_usd_total",
+			Help: "Estimated cost of AI calls in USD.",
+		}, []string{"provider", "model"}),
+		latency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "golangjobsuz_ai_request_duration_seconds",
+			Help:    "Latency of answered AI calls.",
+			Buckets: []float64{0.25, 0.5, 1, 2, 5, 10, 20, 30, 60},
+		}, []string{"provider", "model"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "golangjobsuz_ai_retries_total",
+			Help: "Retried AI calls, by error class.",
+		}, []string{"provider", "model", "class"}),
+		invalid: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "golangjobsuz_ai_validation_failures_total",
+			Help: "AI responses that failed schema validation.",
+		}, []string{"provider", "model"}),
+	}
+	r.Registerer().MustRegister(m.tokens, m.cost, m.latency, m.retries, m.invalid)
+	return m
+}
+
+// ObserveCall records the tokens, cost and latency of an answered call.
+func (m *ExtractionMetrics) ObserveCall(provider, model string, latency time.Duration, promptTokens, completionTokens int, cost float64) {
+	m.tokens.WithLabelValues(provider, model, "input").Add(float64(promptTokens))
+	m.tokens.WithLabelValues(provider, model, "output").Add(float64(completionTokens))
+	m.cost.WithLabelValues(provider, model).Add(cost)
+	m.latency.WithLabelValues(provider, model).Observe(latency.Seconds())
+}
+
+// ObserveRetry counts a retried call.
+func (m *ExtractionMetrics) ObserveRetry(provider, model, class string) {
+	m.retries.WithLabelValues(provider, model, class).Inc()
+}
+
+// ObserveValidationFailure counts a response that failed validation.
+func (m *ExtractionMetrics) ObserveValidationFailure(provider, model string) {
+	m.invalid.WithLabelValues(provider, model).Inc()
+}
+
+// BudgetSpend reports the estimated AI spend of the current day and month for
+// a user, or for the whole system when userID is "". It is implemented by
+// extraction.BudgetGuard.
+type BudgetSpend interface {
+	Spent(userID string) (daily, monthly float64)
+}
+
+// RegisterBudgetSpend exports the system spend counted by budget, read at
+// scrape time, so the gauges match what the budget limits are enforced on.
+func RegisterBudgetSpend(r *Registry, budget BudgetSpend) {
+	r.Registerer().MustRegister(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "golangjobsuz_ai_spend_today_usd",
+			Help: "Estimated AI spend of the current UTC day in USD.",
+		}, func() float64 {
+			daily, _ := budget.Spent("")
+			return daily
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "golangjobsuz_ai_spend_month_usd",
+			Help: "Estimated AI spend of the current UTC month in USD.",
+		}, func() float64 {
+			_, monthly := budget.Spent("")
+			return monthly
+		}),
+	)
+}
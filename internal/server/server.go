@@ -6,10 +6,13 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/Golangjobsuz/golangjobsuz/broadcast"
 	"github.com/Golangjobsuz/golangjobsuz/internal/ai"
 	"github.com/Golangjobsuz/golangjobsuz/internal/config"
 	"github.com/Golangjobsuz/golangjobsuz/internal/handlers"
 	"github.com/Golangjobsuz/golangjobsuz/internal/parser"
+	"github.com/Golangjobsuz/golangjobsuz/internal/platform/database"
+	"github.com/Golangjobsuz/golangjobsuz/internal/platform/metrics"
 	"github.com/Golangjobsuz/golangjobsuz/internal/repo"
 )
 
@@ -29,8 +32,25 @@ func Run(ctx context.Context) error {
 	pipeline := parser.NewPipeline(aiClient, "Extract JSON with title, company, location, description")
 	api := &handlers.API{Parser: pipeline, Repo: repository}
 
-	router := api.Router()
-	srv := &http.Server{Addr: ":" + cfg.Port, Handler: router, ReadHeaderTimeout: 5 * time.Second}
+	mux := http.NewServeMux()
+	mux.Handle("/", api.Router())
+
+	metricsRegistry := metrics.New()
+	mux.Handle("/metrics", metricsRegistry.Handler())
+
+	// Tracked apply links from broadcast cards redirect through /apply/<id>.
+	if cfg.DatabaseURL != "" {
+		pool, err := database.Connect(ctx, cfg.DatabaseURL)
+		if err != nil {
+			return err
+		}
+		defer pool.Close()
+		broadcasts := broadcast.NewService(nil, broadcast.NewPostgresRepo(pool), broadcast.SimpleSummarizer{}, "").
+			WithAnalytics(broadcast.NewPostgresAnalytics(pool), metrics.NewBroadcastMetrics(metricsRegistry))
+		mux.Handle("/apply/", broadcasts.ApplyHandler())
+	}
+
+	srv := &http.Server{Addr: ":" + cfg.Port, Handler: mux, ReadHeaderTimeout: 5 * time.Second}
 
 	go func() {
 		<-ctx.Done()
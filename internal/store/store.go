@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 )
 
@@ -20,7 +21,7 @@ type User struct {
 // RecruiterAccess tracks the approval/ban status for recruiters.
 type RecruiterAccess struct {
 	UserID    string    `json:"user_id"`
-	Status    string    `json:"status"` // pending, approved, banned
+	Status    string    `json:"status"` // pending, approved, suspended, banned
 	UpdatedAt time.Time `json:"updated_at"`
 	UpdatedBy string    `json:"updated_by"`
 	Notes     string    `json:"notes"`
@@ -37,6 +38,8 @@ type Profile struct {
 	UpdatedAt    time.Time `json:"updated_at"`
 	ContactEmail string    `json:"contact_email"`
 	ContactPhone string    `json:"contact_phone"`
+	// Language is the seeker's preferred language for messages, e.g. "uz".
+	Language string `json:"language,omitempty"`
 }
 
 // Store holds all persistent data for the bot.
@@ -45,6 +48,7 @@ type Store struct {
 	RecruiterAccess map[string]RecruiterAccess `json:"recruiter_access"`
 	Profiles        map[string]Profile         `json:"profiles"`
 	path            string                     `json:"-"`
+	mu              sync.RWMutex
 }
 
 // Load reads data from the given path, creating defaults if the file does not exist.
@@ -93,6 +97,50 @@ func (s *Store) Save() error {
 	return nil
 }
 
+// Update reloads the store from disk, applies fn and saves the result, so
+// changes other processes saved since Load are kept. Updates are serialized
+// with each other and with View.
+func (s *Store) Update(fn func(s *Store) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.reload(); err != nil {
+		return err
+	}
+	if err := fn(s); err != nil {
+		return err
+	}
+	return s.Save()
+}
+
+// View calls fn with the store locked against concurrent Updates.
+func (s *Store) View(fn func(s *Store)) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	fn(s)
+}
+
+// reload replaces the in-memory data with the file contents, if the file
+// exists.
+func (s *Store) reload() error {
+	if s.path == "" {
+		return errors.New("store path missing")
+	}
+	content, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read store: %w", err)
+	}
+	var fresh Store
+	if err := json.Unmarshal(content, &fresh); err != nil {
+		return fmt.Errorf("unmarshal store: %w", err)
+	}
+	s.Users, s.RecruiterAccess, s.Profiles = fresh.Users, fresh.RecruiterAccess, fresh.Profiles
+	s.ensureMaps()
+	return nil
+}
+
 // ensureMaps initializes nil maps to avoid nil map panics.
 func (s *Store) ensureMaps() {
 	if s.Users == nil {
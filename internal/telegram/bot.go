@@ -15,10 +15,15 @@ import (
 
 // Bot encapsulates Telegram-specific wiring and lifecycle management.
 type Bot struct {
-	api      *tgbotapi.BotAPI
-	usecases usecase.BotUseCase
-	logger   logger.Logger
-	client   *http.Client
+	api        *tgbotapi.BotAPI
+	usecases   usecase.BotUseCase
+	logger     logger.Logger
+	client     *http.Client
+	moderation *Moderation
+	expiry     *ExpiryReplies
+	consent    *ContactConsent
+	relay      *RelayInbox
+	chats      *ChatBook
 }
 
 // New constructs a Bot with the provided token and dependencies.
@@ -37,6 +42,45 @@ func New(token string, usecases usecase.BotUseCase, log logger.Logger) (*Bot, er
 	return &Bot{api: api, usecases: usecases, logger: log, client: api.Client}, nil
 }
 
+// API returns the underlying Bot API client.
+func (b *Bot) API() *tgbotapi.BotAPI {
+	return b.api
+}
+
+// WithModeration enables the admin commands for reviewing queued broadcasts.
+func (b *Bot) WithModeration(moderation *Moderation) *Bot {
+	b.moderation = moderation
+	return b
+}
+
+// WithExpiryReplies enables the /still_hiring and /filled answers to vacancy
+// expiry reminders.
+func (b *Bot) WithExpiryReplies(replies *ExpiryReplies) *Bot {
+	b.expiry = replies
+	return b
+}
+
+// WithContactConsent enables the seekers' Accept/Decline answers to contact
+// requests.
+func (b *Bot) WithContactConsent(consent *ContactConsent) *Bot {
+	b.consent = consent
+	return b
+}
+
+// WithRelayInbox enables the admin relay threads between recruiters and
+// seekers.
+func (b *Bot) WithRelayInbox(relay *RelayInbox) *Bot {
+	b.relay = relay
+	return b
+}
+
+// WithChatBook records the chat of every user who writes to the bot so
+// ContactNotifier can reach them by @username.
+func (b *Bot) WithChatBook(chats *ChatBook) *Bot {
+	b.chats = chats
+	return b
+}
+
 // Start begins polling for updates and processing incoming messages.
 func (b *Bot) Start(ctx context.Context) error {
 	b.logger.Info().Msg("telegram bot starting")
@@ -60,9 +104,46 @@ func (b *Bot) Start(ctx context.Context) error {
 }
 
 func (b *Bot) handleUpdate(ctx context.Context, update tgbotapi.Update) {
+	if update.CallbackQuery != nil {
+		b.handleCallback(ctx, update.CallbackQuery)
+		return
+	}
 	if update.Message == nil {
 		return
 	}
+	if b.chats != nil && update.Message.From != nil && update.Message.Chat.IsPrivate() {
+		if err := b.chats.Remember(ctx, update.Message.From.UserName, update.Message.Chat.ID); err != nil {
+			b.logger.Error().Err(err).Msg("remember telegram chat")
+		}
+	}
+
+	if b.moderation != nil && update.Message.From != nil {
+		if response, ok := b.moderation.Handle(ctx, update.Message.From.ID, update.Message.Text); ok {
+			b.reply(update.Message.Chat.ID, response)
+			return
+		}
+	}
+	if b.consent != nil && update.Message.From != nil {
+		from := update.Message.From
+		if response, ok := b.consent.Handle(ctx, from.ID, from.UserName, update.Message.Text); ok {
+			b.reply(update.Message.Chat.ID, response)
+			return
+		}
+	}
+	if b.relay != nil && update.Message.From != nil {
+		from := update.Message.From
+		if response, ok := b.relay.Handle(ctx, from.ID, from.UserName, update.Message.Text); ok {
+			b.reply(update.Message.Chat.ID, response)
+			return
+		}
+	}
+	if b.expiry != nil && update.Message.From != nil {
+		from := update.Message.From
+		if response, ok := b.expiry.Handle(ctx, from.ID, from.UserName, update.Message.Text); ok {
+			b.reply(update.Message.Chat.ID, response)
+			return
+		}
+	}
 
 	username := ""
 	firstName := ""
@@ -88,8 +169,37 @@ func (b *Bot) handleUpdate(ctx context.Context, update tgbotapi.Update) {
 		response = "Something went wrong. Please try again."
 	}
 
-	reply := tgbotapi.NewMessage(msg.ChatID, response)
-	if _, err := b.api.Send(reply); err != nil {
+	b.reply(msg.ChatID, response)
+}
+
+// handleCallback answers inline button presses. The buttons are removed once
+// the request is answered so it cannot be answered twice; after a failure
+// they stay for another try.
+func (b *Bot) handleCallback(ctx context.Context, query *tgbotapi.CallbackQuery) {
+	if b.consent == nil || query.From == nil {
+		return
+	}
+	response, answered, ok := b.consent.HandleCallback(ctx, query.From.ID, query.From.UserName, query.Data)
+	if !ok {
+		return
+	}
+	if _, err := b.api.Request(tgbotapi.NewCallback(query.ID, "")); err != nil {
+		b.logger.Error().Err(err).Msg("answer callback query")
+	}
+	if query.Message == nil {
+		return
+	}
+	if answered {
+		removeButtons := tgbotapi.NewEditMessageReplyMarkup(query.Message.Chat.ID, query.Message.MessageID, tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}})
+		if _, err := b.api.Request(removeButtons); err != nil {
+			b.logger.Error().Err(err).Msg("remove consent buttons")
+		}
+	}
+	b.reply(query.Message.Chat.ID, response)
+}
+
+func (b *Bot) reply(chatID int64, text string) {
+	if _, err := b.api.Send(tgbotapi.NewMessage(chatID, text)); err != nil {
 		b.logger.Error().Err(err).Msg("send telegram message")
 	}
 }
@@ -0,0 +1,138 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/Golangjobsuz/golangjobsuz/contact"
+)
+
+// ConsentKeyboard returns the Accept/Decline buttons sent to a seeker with a
+// contact request, and the buttons to report the recruiter.
+func ConsentKeyboard(requestID string) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ Accept", contact.ConsentData(contact.ActionAccept, requestID)),
+			tgbotapi.NewInlineKeyboardButtonData("❌ Decline", contact.ConsentData(contact.ActionDecline, requestID)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🚫 Report spam", contact.ReportData(contact.ReportSpam, requestID)),
+			tgbotapi.NewInlineKeyboardButtonData("⚠️ Report abuse", contact.ReportData(contact.ReportAbuse, requestID)),
+		),
+	)
+}
+
+// ContactConsent handles a seeker's answer to a contact request, given with
+// the ConsentKeyboard buttons or as /accept <id>, /decline <id> and
+// /report <id> spam|abuse [details].
+type ContactConsent struct {
+	service *contact.Service
+}
+
+// NewContactConsent constructs the consent handler.
+func NewContactConsent(service *contact.Service) *ContactConsent {
+	return &ContactConsent{service: service}
+}
+
+// HandleCallback applies a button press and returns the text to show the
+// seeker. answered is true once the request needs no more button presses,
+// because the answer or report was saved or had been already. handled is
+// false when data is not a consent or report button.
+func (c *ContactConsent) HandleCallback(ctx context.Context, userID int64, username, data string) (reply string, answered, handled bool) {
+	if reason, requestID, ok := contact.ParseReportData(data); ok {
+		reply, answered = c.report(ctx, userID, username, requestID, reason, "")
+		return reply, answered, true
+	}
+	action, requestID, ok := contact.ParseConsentData(data)
+	if !ok {
+		return "", false, false
+	}
+	reply, answered = c.respond(ctx, userID, username, action, requestID)
+	return reply, answered, true
+}
+
+// Handle runs /accept or /decline sent by the given user and returns the
+// reply. handled is false when text is not a consent command.
+func (c *ContactConsent) Handle(ctx context.Context, userID int64, username, text string) (reply string, handled bool) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return "", false
+	}
+	action := strings.TrimPrefix(strings.SplitN(fields[0], "@", 2)[0], "/")
+	if action == "report" {
+		if len(fields) < 3 {
+			return "Usage: /report <request id> spam|abuse [details]", true
+		}
+		reply, _ = c.report(ctx, userID, username, fields[1], contact.ReportReason(strings.ToLower(fields[2])), argsAfter(text, 3))
+		return reply, true
+	}
+	if action != contact.ActionAccept && action != contact.ActionDecline {
+		return "", false
+	}
+	if len(fields) < 2 {
+		return fmt.Sprintf("Usage: /%s <request id>", action), true
+	}
+	reply, _ = c.respond(ctx, userID, username, action, fields[1])
+	return reply, true
+}
+
+// respond accepts or declines a request. answered is false when the request
+// is still open, e.g. after a transient failure, so it can be answered again.
+func (c *ContactConsent) respond(ctx context.Context, userID int64, username, action, requestID string) (reply string, answered bool) {
+	if reply, ok := c.checkSeeker(ctx, userID, username, requestID); !ok {
+		return reply, false
+	}
+
+	_, err := c.service.Respond(ctx, action, requestID)
+	switch {
+	case errors.Is(err, contact.ErrInvalidTransition):
+		return "This contact request was already answered.", true
+	case err != nil:
+		return fmt.Sprintf("Could not %s the request: %v", action, err), false
+	case action == contact.ActionAccept:
+		return "Thanks! Your contact details were shared with the recruiter.", true
+	default:
+		return "Declined. The recruiter was told without any of your details.", true
+	}
+}
+
+// report files a report against a request's recruiter. answered is true once
+// the report is saved.
+func (c *ContactConsent) report(ctx context.Context, userID int64, username, requestID string, reason contact.ReportReason, details string) (reply string, answered bool) {
+	if reason != contact.ReportSpam && reason != contact.ReportAbuse {
+		return "Usage: /report <request id> spam|abuse [details]", false
+	}
+	if reply, ok := c.checkSeeker(ctx, userID, username, requestID); !ok {
+		return reply, false
+	}
+
+	entry, err := c.service.Report(ctx, requestID, reason, details)
+	switch {
+	case errors.Is(err, contact.ErrAlreadyReported):
+		return "You already reported this contact request.", true
+	case entry.Report == nil:
+		return fmt.Sprintf("Could not send your report: %v", err), false
+	default:
+		// Errors after the report was saved, such as a failed suspension, are
+		// for the admins to handle.
+		return "Thanks, your report was sent to the admins. The recruiter will not be told about it.", true
+	}
+}
+
+// checkSeeker makes sure the user answering a request is its seeker.
+func (c *ContactConsent) checkSeeker(ctx context.Context, userID int64, username, requestID string) (reply string, ok bool) {
+	entry, err := c.service.Get(ctx, requestID)
+	if err != nil {
+		return "This contact request no longer exists.", false
+	}
+	seeker := entry.Request.SeekerContact
+	if !sameUsername(seeker, username) && seeker != strconv.FormatInt(userID, 10) {
+		return "Only the candidate can answer this contact request.", false
+	}
+	return "", true
+}
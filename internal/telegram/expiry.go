@@ -0,0 +1,77 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Golangjobsuz/golangjobsuz/broadcast"
+)
+
+// ExpiryReplies handles the answers to "still hiring?" reminders:
+//
+//	/still_hiring <id>    keep the vacancy open for another lifetime
+//	/filled <id>          close the vacancy now
+//
+// The posting's contact (matched by @username) and admins may answer.
+type ExpiryReplies struct {
+	service    *broadcast.Service
+	admins     map[int64]bool
+	markClosed bool
+}
+
+// NewExpiryReplies constructs the reminder reply commands. Filled vacancies
+// get a CLOSED banner when markClosed is set and are deleted otherwise.
+func NewExpiryReplies(service *broadcast.Service, adminIDs []int64, markClosed bool) *ExpiryReplies {
+	admins := make(map[int64]bool, len(adminIDs))
+	for _, id := range adminIDs {
+		admins[id] = true
+	}
+	return &ExpiryReplies{service: service, admins: admins, markClosed: markClosed}
+}
+
+// Handle runs a reminder reply sent by the given user and returns the reply.
+// handled is false when text is not a reminder reply.
+func (e *ExpiryReplies) Handle(ctx context.Context, userID int64, username, text string) (reply string, handled bool) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return "", false
+	}
+	command := strings.SplitN(fields[0], "@", 2)[0]
+	if command != "/still_hiring" && command != "/filled" {
+		return "", false
+	}
+	if len(fields) < 2 {
+		return fmt.Sprintf("Usage: %s <broadcast id>", command), true
+	}
+
+	id := fields[1]
+	record, err := e.service.Get(ctx, id)
+	if err != nil {
+		return fmt.Sprintf("Could not find broadcast %s.", id), true
+	}
+	if !e.admins[userID] && !sameUsername(record.Job.Contact, username) {
+		return "Only the vacancy contact can answer this reminder.", true
+	}
+
+	if command == "/still_hiring" {
+		record, err = e.service.ExtendBroadcast(ctx, id, time.Time{})
+		if err != nil {
+			return fmt.Sprintf("Could not extend broadcast %s: %v", id, err), true
+		}
+		return fmt.Sprintf("Thanks! %q stays open until %s.", record.Job.Title, record.Job.ExpiresAt.Format("2006-01-02")), true
+	}
+
+	if _, err := e.service.RetractBroadcast(ctx, id, e.markClosed); err != nil {
+		return fmt.Sprintf("Could not close broadcast %s: %v", id, err), true
+	}
+	return fmt.Sprintf("Thanks! %q is now closed.", record.Job.Title), true
+}
+
+// sameUsername reports whether a posting contact such as "@hr_team" names the
+// given Telegram username.
+func sameUsername(contact, username string) bool {
+	contact = strings.TrimPrefix(strings.TrimSpace(contact), "@")
+	return username != "" && strings.EqualFold(contact, username)
+}
@@ -0,0 +1,145 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Golangjobsuz/golangjobsuz/broadcast"
+)
+
+// Moderation handles the admin commands that review queued broadcasts:
+//
+//	/pending                 list broadcasts waiting for review
+//	/approve <id>            approve and queue for sending
+//	/reject <id> [reason]    reject without sending
+//	/edit <id>               followed by "Field: value" lines
+type Moderation struct {
+	service *broadcast.Service
+	admins  map[int64]bool
+}
+
+// NewModeration constructs the moderation commands for the given admins.
+func NewModeration(service *broadcast.Service, adminIDs []int64) *Moderation {
+	admins := make(map[int64]bool, len(adminIDs))
+	for _, id := range adminIDs {
+		admins[id] = true
+	}
+	return &Moderation{service: service, admins: admins}
+}
+
+// ParseAdminIDs parses a comma separated list of Telegram user IDs such as
+// the ADMIN_IDS setting.
+func ParseAdminIDs(raw string) ([]int64, error) {
+	var ids []int64
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid admin ID %q: %w", part, err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// Handle runs a moderation command sent by userID and returns the reply.
+// handled is false when text is not a moderation command.
+func (m *Moderation) Handle(ctx context.Context, userID int64, text string) (reply string, handled bool) {
+	firstLine, rest, _ := strings.Cut(strings.TrimSpace(text), "\n")
+	fields := strings.Fields(firstLine)
+	if len(fields) == 0 {
+		return "", false
+	}
+	command := strings.SplitN(fields[0], "@", 2)[0]
+	switch command {
+	case "/pending", "/approve", "/reject", "/edit":
+	default:
+		return "", false
+	}
+
+	if !m.admins[userID] {
+		return "Only admins can review broadcasts.", true
+	}
+	reviewer := strconv.FormatInt(userID, 10)
+	args := fields[1:]
+
+	if command == "/pending" {
+		return m.pending(ctx), true
+	}
+	if len(args) == 0 {
+		return fmt.Sprintf("Usage: %s <broadcast id>", command), true
+	}
+
+	id := args[0]
+	var err error
+	switch command {
+	case "/approve":
+		_, err = m.service.ApproveBroadcast(ctx, id, reviewer)
+		reply = fmt.Sprintf("Broadcast %s approved and queued for sending.", id)
+	case "/reject":
+		_, err = m.service.RejectBroadcast(ctx, id, reviewer, strings.Join(args[1:], " "))
+		reply = fmt.Sprintf("Broadcast %s rejected.", id)
+	case "/edit":
+		err = m.edit(ctx, id, reviewer, rest)
+		reply = fmt.Sprintf("Broadcast %s updated; /approve %s to send it.", id, id)
+	}
+	if err != nil {
+		return fmt.Sprintf("Could not %s broadcast %s: %v", strings.TrimPrefix(command, "/"), id, err), true
+	}
+	return reply, true
+}
+
+func (m *Moderation) pending(ctx context.Context) string {
+	records, err := m.service.PendingReview(ctx)
+	if err != nil {
+		return fmt.Sprintf("Could not load the review queue: %v", err)
+	}
+	if len(records) == 0 {
+		return "No broadcasts are waiting for review."
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d broadcast(s) waiting for review:\n", len(records))
+	for _, record := range records {
+		fmt.Fprintf(&b, "\n%s: %s at %s -> %s\n", record.ID, record.Job.Title, record.Job.Company, record.Channel)
+		if record.Summary != "" {
+			fmt.Fprintf(&b, "%s\n", record.Summary)
+		}
+	}
+	b.WriteString("\nReply /approve <id>, /reject <id> [reason] or /edit <id> with \"Field: value\" lines.")
+	return b.String()
+}
+
+// edit applies "Field: value" lines to the queued posting.
+func (m *Moderation) edit(ctx context.Context, id, reviewer, body string) error {
+	changes := make(map[string]string)
+	for _, line := range strings.Split(body, "\n") {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return fmt.Errorf("expected \"Field: value\", got %q", line)
+		}
+		changes[name] = value
+	}
+	if len(changes) == 0 {
+		return fmt.Errorf("no fields to change")
+	}
+
+	record, err := m.service.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	posting, err := record.Job.WithFields(changes)
+	if err != nil {
+		return err
+	}
+	_, err = m.service.EditPendingBroadcast(ctx, id, reviewer, posting)
+	return err
+}
@@ -0,0 +1,189 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/Golangjobsuz/golangjobsuz/contact"
+)
+
+// ChatBook remembers the private chat of every user who has written to the
+// bot, so users can be messaged by @username. The Bot fills it from incoming
+// messages when set with WithChatBook. A book loaded with LoadChatBook also
+// writes new and changed chats to its ChatStore, so they survive restarts.
+type ChatBook struct {
+	mu    sync.RWMutex
+	chats map[string]int64
+	store ChatStore
+}
+
+// ChatStore persists the chats of a ChatBook.
+type ChatStore interface {
+	SaveChat(ctx context.Context, username string, chatID int64) error
+	LoadChats(ctx context.Context) (map[string]int64, error)
+}
+
+// NewChatBook constructs an empty chat book kept in memory only.
+func NewChatBook() *ChatBook {
+	return &ChatBook{chats: make(map[string]int64)}
+}
+
+// LoadChatBook constructs a chat book holding the chats saved in store.
+func LoadChatBook(ctx context.Context, store ChatStore) (*ChatBook, error) {
+	chats, err := store.LoadChats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load telegram chats: %w", err)
+	}
+	book := &ChatBook{chats: make(map[string]int64, len(chats)), store: store}
+	for username, chatID := range chats {
+		if key := normalizeUsername(username); key != "" {
+			book.chats[key] = chatID
+		}
+	}
+	return book, nil
+}
+
+// Remember records the chat ID of a user, saving it to the store when it is
+// new or changed. The chat is remembered in memory even if saving fails.
+func (c *ChatBook) Remember(ctx context.Context, username string, chatID int64) error {
+	key := normalizeUsername(username)
+	if key == "" {
+		return nil
+	}
+	c.mu.Lock()
+	known, ok := c.chats[key]
+	c.chats[key] = chatID
+	c.mu.Unlock()
+
+	if c.store == nil || (ok && known == chatID) {
+		return nil
+	}
+	if err := c.store.SaveChat(ctx, key, chatID); err != nil {
+		return fmt.Errorf("save telegram chat of %s: %w", key, err)
+	}
+	return nil
+}
+
+// Lookup returns the chat ID of a user who has written to the bot.
+func (c *ChatBook) Lookup(username string) (int64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	id, ok := c.chats[normalizeUsername(username)]
+	return id, ok
+}
+
+func normalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(username), "@"))
+}
+
+// sender is the subset of tgbotapi.BotAPI used by ContactNotifier.
+type sender interface {
+	Send(c tgbotapi.Chattable) (tgbotapi.Message, error)
+}
+
+// ContactNotifier delivers contact requests over Telegram. It implements
+// contact.Notifier, contact.ConsentNotifier and contact.RecruiterNotifier.
+// Seekers and recruiters are addressed by chat ID or @username; a user the
+// bot cannot message, because they never started it, blocked it or gave
+// another kind of contact, is reported as contact.ErrUnreachable.
+type ContactNotifier struct {
+	api    sender
+	chats  *ChatBook
+	admins []int64
+}
+
+// NewContactNotifier constructs a notifier that resolves usernames with chats
+// and sends admin messages to every chat in adminIDs.
+func NewContactNotifier(api *tgbotapi.BotAPI, chats *ChatBook, adminIDs []int64) *ContactNotifier {
+	return &ContactNotifier{api: api, chats: chats, admins: adminIDs}
+}
+
+// NotifySeeker implements contact.Notifier.
+func (n *ContactNotifier) NotifySeeker(ctx context.Context, seekerContact, message string) error {
+	return n.sendTo(ctx, seekerContact, message, nil)
+}
+
+// AskSeeker implements contact.ConsentNotifier with Accept/Decline buttons.
+func (n *ContactNotifier) AskSeeker(ctx context.Context, seekerContact, message, requestID string) error {
+	keyboard := ConsentKeyboard(requestID)
+	return n.sendTo(ctx, seekerContact, message, &keyboard)
+}
+
+// NotifyRecruiter implements contact.RecruiterNotifier.
+func (n *ContactNotifier) NotifyRecruiter(ctx context.Context, recruiterContact, message string) error {
+	return n.sendTo(ctx, recruiterContact, message, nil)
+}
+
+// NotifyAdmin implements contact.Notifier. It succeeds when at least one
+// admin received the message.
+func (n *ContactNotifier) NotifyAdmin(ctx context.Context, message string) error {
+	if len(n.admins) == 0 {
+		return errors.New("no admin chats configured")
+	}
+	var errs []error
+	for _, id := range n.admins {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if _, err := n.api.Send(tgbotapi.NewMessage(id, message)); err != nil {
+			errs = append(errs, fmt.Errorf("admin %d: %w", id, err))
+		}
+	}
+	if len(errs) == len(n.admins) {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+func (n *ContactNotifier) sendTo(ctx context.Context, to, message string, keyboard *tgbotapi.InlineKeyboardMarkup) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	chatID, err := n.resolve(to)
+	if err != nil {
+		return err
+	}
+
+	msg := tgbotapi.NewMessage(chatID, message)
+	if keyboard != nil {
+		msg.ReplyMarkup = *keyboard
+	}
+	if _, err := n.api.Send(msg); err != nil {
+		return wrapSendError(to, err)
+	}
+	return nil
+}
+
+// resolve turns a contact into a private chat ID. Telegram only lets bots
+// message users who have started them, so usernames must be in the chat book.
+func (n *ContactNotifier) resolve(to string) (int64, error) {
+	to = strings.TrimSpace(to)
+	if id, err := strconv.ParseInt(to, 10, 64); err == nil {
+		return id, nil
+	}
+	if !strings.HasPrefix(to, "@") {
+		return 0, fmt.Errorf("%w: %q is not a Telegram chat ID or @username", contact.ErrUnreachable, to)
+	}
+	if n.chats != nil {
+		if id, ok := n.chats.Lookup(to); ok {
+			return id, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %s has not started the bot", contact.ErrUnreachable, to)
+}
+
+// wrapSendError marks the Bot API errors for users the bot may not message
+// as contact.ErrUnreachable.
+func wrapSendError(to string, err error) error {
+	var apiErr *tgbotapi.Error
+	if errors.As(err, &apiErr) && (apiErr.Code == 403 || strings.Contains(apiErr.Message, "chat not found")) {
+		return fmt.Errorf("%w: %s: %s", contact.ErrUnreachable, to, apiErr.Message)
+	}
+	return fmt.Errorf("send to %s: %w", to, err)
+}
@@ -0,0 +1,60 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresChatStore keeps ChatBook chats in the telegram_chats table.
+type PostgresChatStore struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresChatStore constructs a chat store backed by the given pool.
+func NewPostgresChatStore(pool *pgxpool.Pool) *PostgresChatStore {
+	return &PostgresChatStore{pool: pool}
+}
+
+// SaveChat implements ChatStore.
+func (s *PostgresChatStore) SaveChat(ctx context.Context, username string, chatID int64) error {
+	_, err := s.pool.Exec(ctx, `
+INSERT INTO telegram_chats (username, chat_id, updated_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (username) DO UPDATE SET
+    chat_id = EXCLUDED.chat_id,
+    updated_at = EXCLUDED.updated_at`,
+		username, chatID, time.Now().UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("save telegram chat %s: %w", username, err)
+	}
+	return nil
+}
+
+// LoadChats implements ChatStore.
+func (s *PostgresChatStore) LoadChats(ctx context.Context) (map[string]int64, error) {
+	rows, err := s.pool.Query(ctx, `SELECT username, chat_id FROM telegram_chats`)
+	if err != nil {
+		return nil, fmt.Errorf("query telegram chats: %w", err)
+	}
+	defer rows.Close()
+
+	chats := make(map[string]int64)
+	for rows.Next() {
+		var (
+			username string
+			chatID   int64
+		)
+		if err := rows.Scan(&username, &chatID); err != nil {
+			return nil, fmt.Errorf("scan telegram chat: %w", err)
+		}
+		chats[username] = chatID
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read telegram chats: %w", err)
+	}
+	return chats, nil
+}
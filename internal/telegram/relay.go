@@ -0,0 +1,148 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Golangjobsuz/golangjobsuz/contact"
+)
+
+// RelayInbox handles admin-relayed conversations between recruiters and
+// seekers:
+//
+//	/threads                           list open relay threads (admins)
+//	/relay_forward <id> [edited text]  forward the pending message (admins)
+//	/relay_reject <id> [reason]        drop the pending message (admins)
+//	/relay_close <id>                  close the thread (admins)
+//	/reply <id> <text>                 answer in a thread (recruiter or seeker)
+type RelayInbox struct {
+	service *contact.Service
+	admins  map[int64]bool
+}
+
+// NewRelayInbox constructs the relay commands for the given admins.
+func NewRelayInbox(service *contact.Service, adminIDs []int64) *RelayInbox {
+	admins := make(map[int64]bool, len(adminIDs))
+	for _, id := range adminIDs {
+		admins[id] = true
+	}
+	return &RelayInbox{service: service, admins: admins}
+}
+
+// Handle runs a relay command sent by the given user and returns the reply.
+// handled is false when text is not a relay command.
+func (r *RelayInbox) Handle(ctx context.Context, userID int64, username, text string) (reply string, handled bool) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return "", false
+	}
+	command := strings.SplitN(fields[0], "@", 2)[0]
+	switch command {
+	case "/threads", "/relay_forward", "/relay_reject", "/relay_close", "/reply":
+	default:
+		return "", false
+	}
+
+	if command == "/reply" {
+		return r.reply(ctx, userID, username, text), true
+	}
+	if !r.admins[userID] {
+		return "Only admins can manage relay threads.", true
+	}
+	if command == "/threads" {
+		return r.threads(ctx), true
+	}
+	if len(fields) < 2 {
+		return fmt.Sprintf("Usage: %s <thread id>", command), true
+	}
+
+	id, rest := fields[1], argsAfter(text, 2)
+	admin := strconv.FormatInt(userID, 10)
+	var err error
+	switch command {
+	case "/relay_forward":
+		_, err = r.service.ForwardRelay(ctx, id, admin, rest)
+		reply = fmt.Sprintf("Message in thread %s forwarded.", id)
+	case "/relay_reject":
+		_, err = r.service.RejectRelay(ctx, id, admin, rest)
+		reply = fmt.Sprintf("Message in thread %s rejected.", id)
+	case "/relay_close":
+		_, err = r.service.CloseRelay(ctx, id, admin)
+		reply = fmt.Sprintf("Thread %s closed.", id)
+	}
+	if err != nil {
+		return fmt.Sprintf("Could not update thread %s: %v", id, err), true
+	}
+	return reply, true
+}
+
+// reply adds a message from the recruiter or seeker of a thread.
+func (r *RelayInbox) reply(ctx context.Context, userID int64, username, text string) string {
+	fields := strings.Fields(text)
+	message := argsAfter(text, 2)
+	if len(fields) < 2 || message == "" {
+		return "Usage: /reply <thread id> <message>"
+	}
+
+	id := fields[1]
+	thread, err := r.service.GetThread(ctx, id)
+	if err != nil {
+		return "This conversation no longer exists."
+	}
+	user := strconv.FormatInt(userID, 10)
+	var from contact.Party
+	switch {
+	case sameUsername(thread.Request.SeekerContact, username) || thread.Request.SeekerContact == user:
+		from = contact.PartySeeker
+	case sameUsername(thread.Request.RecruiterContact, username) || thread.Request.RecruiterContact == user:
+		from = contact.PartyRecruiter
+	default:
+		return "You are not part of this conversation."
+	}
+
+	if _, err := r.service.ReplyInThread(ctx, id, from, message); err != nil {
+		return fmt.Sprintf("Could not send your message: %v", err)
+	}
+	return "Your message was sent to the admins and will be forwarded after review."
+}
+
+func (r *RelayInbox) threads(ctx context.Context) string {
+	threads, err := r.service.OpenThreads(ctx)
+	if err != nil {
+		return fmt.Sprintf("Could not load relay threads: %v", err)
+	}
+	if len(threads) == 0 {
+		return "No open relay threads."
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d open relay thread(s):\n", len(threads))
+	for _, thread := range threads {
+		state := "no messages waiting"
+		if i := thread.Pending(); i >= 0 {
+			state = fmt.Sprintf("waiting: %q from the %s", thread.Messages[i].Text, thread.Messages[i].From)
+		}
+		if thread.SeekerUnreachable != "" {
+			state += fmt.Sprintf("\nseeker not reachable by the bot (%s): forwarding to them fails until it can", thread.SeekerUnreachable)
+		}
+		fmt.Fprintf(&b, "\n%s: %s (%s) -> %s about %s\n%s\n", thread.ID, thread.Request.RecruiterName,
+			thread.Request.RecruiterCompany, thread.Request.SeekerName, thread.Request.Role, state)
+	}
+	return b.String()
+}
+
+// argsAfter returns the text following the first n whitespace-separated
+// fields, keeping its line breaks.
+func argsAfter(text string, n int) string {
+	rest := strings.TrimSpace(text)
+	for i := 0; i < n; i++ {
+		j := strings.IndexFunc(rest, func(r rune) bool { return r == ' ' || r == '\n' || r == '\t' })
+		if j < 0 {
+			return ""
+		}
+		rest = strings.TrimSpace(rest[j:])
+	}
+	return rest
+}
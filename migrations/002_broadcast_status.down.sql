@@ -0,0 +1,12 @@
+DROP INDEX IF EXISTS idx_broadcasts_created_at;
+DROP INDEX IF EXISTS idx_broadcasts_status;
+DROP INDEX IF EXISTS idx_broadcasts_record_id;
+
+ALTER TABLE broadcasts
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS dry_run,
+    DROP COLUMN IF EXISTS errors,
+    DROP COLUMN IF EXISTS attempts,
+    DROP COLUMN IF EXISTS status,
+    DROP COLUMN IF EXISTS job,
+    DROP COLUMN IF EXISTS record_id;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_broadcasts_scheduled_at;
+
+ALTER TABLE broadcasts
+    DROP COLUMN IF EXISTS max_retries,
+    DROP COLUMN IF EXISTS scheduled_at;
@@ -0,0 +1,6 @@
+-- Scheduled broadcasts are delivered by broadcast.Dispatcher once due.
+ALTER TABLE broadcasts
+    ADD COLUMN IF NOT EXISTS scheduled_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS max_retries INTEGER NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_broadcasts_scheduled_at ON broadcasts(scheduled_at) WHERE status = 'scheduled';
@@ -0,0 +1,3 @@
+-- Per-attempt backoff delays recorded by broadcast.Service.
+ALTER TABLE broadcasts
+    ADD COLUMN IF NOT EXISTS retry_waits JSONB NOT NULL DEFAULT '[]'::jsonb;
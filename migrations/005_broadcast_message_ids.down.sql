@@ -0,0 +1,2 @@
+ALTER TABLE broadcasts
+    DROP COLUMN IF EXISTS message_ids;
@@ -0,0 +1,3 @@
+-- Channel message IDs returned by the sender, used to edit or delete posts.
+ALTER TABLE broadcasts
+    ADD COLUMN IF NOT EXISTS message_ids JSONB NOT NULL DEFAULT '[]'::jsonb;
@@ -0,0 +1,3 @@
+-- Edit and retraction history for published broadcasts.
+ALTER TABLE broadcasts
+    ADD COLUMN IF NOT EXISTS history JSONB NOT NULL DEFAULT '[]'::jsonb;
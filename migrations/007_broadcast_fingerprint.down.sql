@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_broadcasts_fingerprint;
+
+ALTER TABLE broadcasts
+    DROP COLUMN IF EXISTS duplicate_of,
+    DROP COLUMN IF EXISTS fingerprint;
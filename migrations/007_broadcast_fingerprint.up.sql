@@ -0,0 +1,6 @@
+-- Duplicate vacancy detection: normalized posting hash and link to the original.
+ALTER TABLE broadcasts
+    ADD COLUMN IF NOT EXISTS fingerprint TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS duplicate_of TEXT NOT NULL DEFAULT '';
+
+CREATE INDEX IF NOT EXISTS idx_broadcasts_fingerprint ON broadcasts(fingerprint);
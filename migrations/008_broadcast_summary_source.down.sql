@@ -0,0 +1,3 @@
+ALTER TABLE broadcasts
+    DROP COLUMN IF EXISTS summary_model,
+    DROP COLUMN IF EXISTS summary_by;
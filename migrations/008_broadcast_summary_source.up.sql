@@ -0,0 +1,4 @@
+-- Which summarizer and model produced the broadcast summary.
+ALTER TABLE broadcasts
+    ADD COLUMN IF NOT EXISTS summary_by TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS summary_model TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+-- Card rendered for dry-run broadcasts.
+ALTER TABLE broadcasts
+    ADD COLUMN IF NOT EXISTS preview TEXT NOT NULL DEFAULT '';
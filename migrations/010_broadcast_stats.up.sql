@@ -0,0 +1,8 @@
+-- Engagement counters for published broadcasts, keyed by broadcasts.record_id.
+CREATE TABLE IF NOT EXISTS broadcast_stats (
+    record_id TEXT PRIMARY KEY,
+    clicks BIGINT NOT NULL DEFAULT 0,
+    views BIGINT NOT NULL DEFAULT 0,
+    last_click_at TIMESTAMPTZ,
+    views_updated_at TIMESTAMPTZ
+);
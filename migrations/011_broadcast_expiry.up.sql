@@ -0,0 +1,3 @@
+-- "Still hiring?" reminders; the expiry date itself is part of the job JSON.
+ALTER TABLE broadcasts
+    ADD COLUMN IF NOT EXISTS reminder_sent_at TIMESTAMPTZ;
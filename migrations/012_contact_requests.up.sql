@@ -0,0 +1,14 @@
+-- Recruiter contact requests and their lifecycle (requested, delivered,
+-- accepted, declined, expired).
+CREATE TABLE IF NOT EXISTS contact_requests (
+    request_id TEXT PRIMARY KEY,
+    request JSONB NOT NULL,
+    status TEXT NOT NULL DEFAULT 'requested',
+    delivered BOOLEAN NOT NULL DEFAULT FALSE,
+    via_admin BOOLEAN NOT NULL DEFAULT FALSE,
+    error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS contact_requests_status_idx ON contact_requests (status, created_at);
@@ -0,0 +1,2 @@
+ALTER TABLE contact_requests
+    DROP COLUMN IF EXISTS access_expires_at;
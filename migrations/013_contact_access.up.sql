@@ -0,0 +1,3 @@
+-- When the recruiter_access granted on acceptance of a request ends.
+ALTER TABLE contact_requests
+    ADD COLUMN IF NOT EXISTS access_expires_at TIMESTAMPTZ;
@@ -0,0 +1,13 @@
+-- Admin-relayed conversations between recruiters and seekers. Messages are
+-- kept as JSON with their review state.
+CREATE TABLE IF NOT EXISTS contact_relay_threads (
+    thread_id TEXT PRIMARY KEY,
+    request_id TEXT NOT NULL,
+    request JSONB NOT NULL,
+    status TEXT NOT NULL DEFAULT 'open',
+    messages JSONB NOT NULL DEFAULT '[]'::jsonb,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS contact_relay_threads_status_idx ON contact_relay_threads (status, created_at);
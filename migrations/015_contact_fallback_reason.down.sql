@@ -0,0 +1,2 @@
+ALTER TABLE contact_requests
+    DROP COLUMN IF EXISTS fallback_reason;
@@ -0,0 +1,3 @@
+-- Why a request meant for the seeker went through the admin relay instead.
+ALTER TABLE contact_requests
+    ADD COLUMN IF NOT EXISTS fallback_reason TEXT NOT NULL DEFAULT '';
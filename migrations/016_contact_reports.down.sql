@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS contact_requests_reported_idx;
+
+ALTER TABLE contact_requests
+    DROP COLUMN IF EXISTS report;
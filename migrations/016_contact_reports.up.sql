@@ -0,0 +1,6 @@
+-- Seeker reports of spam or abuse, one per contact request.
+ALTER TABLE contact_requests
+    ADD COLUMN IF NOT EXISTS report JSONB;
+
+CREATE INDEX IF NOT EXISTS contact_requests_reported_idx ON contact_requests (created_at)
+    WHERE report IS NOT NULL;
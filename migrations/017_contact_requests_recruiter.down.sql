@@ -0,0 +1 @@
+DROP INDEX IF EXISTS contact_requests_recruiter_idx;
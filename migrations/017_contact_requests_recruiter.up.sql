@@ -0,0 +1,3 @@
+-- Quota checks look up one recruiter's recent contact requests.
+CREATE INDEX IF NOT EXISTS contact_requests_recruiter_idx
+    ON contact_requests ((request->>'RecruiterID'), created_at);
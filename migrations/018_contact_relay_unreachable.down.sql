@@ -0,0 +1,2 @@
+ALTER TABLE contact_relay_threads
+    DROP COLUMN IF EXISTS seeker_unreachable;
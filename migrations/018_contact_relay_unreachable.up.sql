@@ -0,0 +1,3 @@
+-- Why the seeker of a relay thread could not be reached directly.
+ALTER TABLE contact_relay_threads
+    ADD COLUMN IF NOT EXISTS seeker_unreachable TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,7 @@
+-- Private chats of users who wrote to the bot, so they can be messaged by
+-- @username after a restart. Usernames are stored lower case without the @.
+CREATE TABLE IF NOT EXISTS telegram_chats (
+    username TEXT PRIMARY KEY,
+    chat_id BIGINT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,10 @@
+-- Estimated cost of every AI extraction, so budget limits survive restarts.
+-- user_id is empty for extractions made without a user.
+CREATE TABLE IF NOT EXISTS ai_spend (
+    id BIGSERIAL PRIMARY KEY,
+    user_id TEXT NOT NULL DEFAULT '',
+    cost_usd DOUBLE PRECISION NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS ai_spend_created_at_idx ON ai_spend (created_at);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS broadcasts_sent_at_idx;
@@ -0,0 +1,3 @@
+-- Digests select broadcasts by the time they were last sent.
+CREATE INDEX IF NOT EXISTS broadcasts_sent_at_idx ON broadcasts (sent_at)
+    WHERE record_id IS NOT NULL;